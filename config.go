@@ -1,34 +1,150 @@
 package icmpreceiver
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
-	"sync"
+	"net"
+	"net/netip"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
 	"go.uber.org/multierr"
+
+	"github.com/supersun/otel-icmp-receiver/internal/metadata"
 )
 
-var (
-	mu                     sync.Mutex              // mu is used to synchronize access to globalTargets
-	globalTargets          = make(map[string]bool) // globalTargets is used to store all targets to check for duplicates
-	errNonPositiveInterval = errors.New("requires positive value")
+var errNonPositiveInterval = errors.New("requires positive value")
+
+const (
+	// minPacketSize is the smallest payload pro-bing can send, as it embeds a
+	// timestamp and a tracker UUID in every packet.
+	minPacketSize = 24
+	maxPacketSize = 65507
+	maxTTL        = 255
+	// maxInterfaceNameLength is IFNAMSIZ minus the trailing NUL byte.
+	maxInterfaceNameLength = 15
+	// proberPingInterval is the interval pro-bing sends pings at when none is
+	// set.
+	proberPingInterval = time.Second
+)
+
+// reservedAttributes are set by the receiver and cannot be used as custom
+// target attributes.
+var reservedAttributes = []string{
+	AttrPeerIp, AttrPeerName, AttrPeerPrefix, AttrSockFamily, AttrHostIp, AttrHostInterface, AttrTag,
+}
+
+const (
+	AddressFamilyIPv4 = "ip4"
+	AddressFamilyIPv6 = "ip6"
+	AddressFamilyAny  = "any"
 )
 
 type Config struct {
 	scraperhelper.ControllerConfig `mapstructure:",squash"`
+	metadata.MetricsBuilderConfig  `mapstructure:",squash"`
 	Targets                        []Target      `mapstructure:"targets"`
 	DefaultPingCount               int           `mapstructure:"default_ping_count"`
 	DefaultPingTimeout             time.Duration `mapstructure:"default_ping_timeout"`
 	Tag                            string        `mapstructure:"tag"`
+	MaxConcurrency                 int           `mapstructure:"max_concurrency"`
+	Privileged                     bool          `mapstructure:"privileged"`
+	AddressFamily                  string        `mapstructure:"address_family"`
+	DefaultPacketSize              int           `mapstructure:"default_packet_size"`
+	DefaultTTL                     int           `mapstructure:"default_ttl"`
+	DefaultPingInterval            time.Duration `mapstructure:"default_ping_interval"`
+	Source                         string        `mapstructure:"source"`
+	RttMode                        string        `mapstructure:"rtt_mode"`
+	RttHistogramBuckets            []float64     `mapstructure:"rtt_histogram_buckets"`
+	RttHistogramMaxBuckets         int           `mapstructure:"rtt_histogram_max_buckets"`
+	RttTrimRatio                   float64       `mapstructure:"rtt_trim_ratio"`
+	MaxTargetExpansion             int           `mapstructure:"max_target_expansion"`
+	// ExclusiveTargets rejects, at start, targets already pinged by another
+	// receiver that also sets it.
+	ExclusiveTargets bool `mapstructure:"exclusive_targets"`
+	// TargetsFile is a Prometheus file_sd file of targets pinged along with
+	// Targets. It is read again whenever it changes.
+	TargetsFile string `mapstructure:"targets_file"`
+	// Resolver configures how host names are resolved.
+	Resolver ResolverConfig `mapstructure:"resolver"`
+	// Mode is burst, to send a burst of pings at every scrape, or continuous,
+	// to keep pinging the targets between scrapes.
+	Mode string `mapstructure:"mode"`
+	// Spread delays the probe of every target by a random offset below it, so
+	// that targets and receivers are not pinged all at once.
+	Spread time.Duration `mapstructure:"spread"`
+	// Seed seeds the random offsets and intervals. 0 picks a random seed at
+	// start.
+	Seed uint64 `mapstructure:"seed"`
+	// StateChanges configures the log records emitted when the reachability
+	// state of a target changes, if the receiver is in a logs pipeline.
+	StateChanges StateChangesConfig `mapstructure:"state_changes"`
+	// SeriesExpiryScrapes is the number of scrapes of a target that do not
+	// report one of its cumulative series, e.g. of an address its host name no
+	// longer resolves to, after which the series is dropped. 0 keeps them.
+	SeriesExpiryScrapes int `mapstructure:"series_expiry_scrapes"`
+}
+
+type StateChangesConfig struct {
+	// LossThreshold is the loss ratio from which a target that answers is
+	// degraded. 0 never reports targets as degraded.
+	LossThreshold float64 `mapstructure:"loss_threshold"`
+	// FailureThreshold is the number of consecutive results that must find a
+	// target down or degraded before it changes state.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// RecoveryThreshold is the number of consecutive results that must find a
+	// target up before it recovers.
+	RecoveryThreshold int `mapstructure:"recovery_threshold"`
+}
+
+type ResolverConfig struct {
+	// Server is the IP address, with an optional port, of a DNS server to
+	// query instead of the system resolver. Its answers are cached for their TTL.
+	Server   string        `mapstructure:"server"`
+	Protocol string        `mapstructure:"protocol"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// CacheTTL is how long answers of the system resolver, which does not
+	// report record TTLs, are cached. 0 disables caching.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
 }
 
 type Target struct {
 	Target string `mapstructure:"target"`
 
-	PingCount   *int           `mapstructure:"ping_count"`
-	PingTimeout *time.Duration `mapstructure:"ping_timeout"`
+	PingCount     *int           `mapstructure:"ping_count"`
+	PingTimeout   *time.Duration `mapstructure:"ping_timeout"`
+	Privileged    *bool          `mapstructure:"privileged"`
+	AddressFamily string         `mapstructure:"address_family"`
+	PacketSize    *int           `mapstructure:"packet_size"`
+	TTL           *int           `mapstructure:"ttl"`
+	PingInterval  *time.Duration `mapstructure:"ping_interval"`
+	Source        string         `mapstructure:"source"`
+	Tag           string         `mapstructure:"tag"`
+	// Attributes are added to every data point of the target.
+	Attributes map[string]string `mapstructure:"attributes"`
+	// Exclude lists addresses, prefixes or ranges not to ping when Target is a
+	// CIDR prefix or a range.
+	Exclude []string `mapstructure:"exclude"`
+	// ResolveMode selects which addresses of a host name are pinged: one of
+	// them (first, the default), all of them (all), or all the addresses of
+	// the targets of an SRV record (srv).
+	ResolveMode string `mapstructure:"resolve_mode"`
+	// Interval pings the target on its own cadence instead of at every scrape.
+	// Its results are reported by the next scrape once they complete.
+	Interval *time.Duration `mapstructure:"interval"`
+	// Spread overrides the receiver-wide spread for this target.
+	Spread *time.Duration `mapstructure:"spread"`
+	// Schedule is periodic, the default, to ping a target with an interval at
+	// a fixed period, or poisson to draw the time between two runs from an
+	// exponential distribution of mean interval.
+	Schedule string `mapstructure:"schedule"`
+
+	// prefix is the CIDR prefix or range the target was expanded from.
+	prefix string
 }
 
 func (c *Config) Validate() (errs error) {
@@ -52,31 +168,404 @@ func (c *Config) Validate() (errs error) {
 		errs = multierr.Append(errs, fmt.Errorf(`"default_ping_timeout": %s`, "cannot be lesser than 5s"))
 	}
 
-	if len(c.Targets) == 0 {
+	if c.DefaultPacketSize != 0 && !isValidPacketSize(c.DefaultPacketSize) {
+		errs = multierr.Append(errs, fmt.Errorf(`"default_packet_size": must be between %d and %d`, minPacketSize, maxPacketSize))
+	}
+	if c.DefaultTTL != 0 && !isValidTTL(c.DefaultTTL) {
+		errs = multierr.Append(errs, fmt.Errorf(`"default_ttl": must be between 1 and %d`, maxTTL))
+	}
+	interval := cmp.Or(c.DefaultPingInterval, proberPingInterval)
+	if c.DefaultPingInterval < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"default_ping_interval": %w`, errNonPositiveInterval))
+	} else if time.Duration(c.DefaultPingCount-1)*interval > c.DefaultPingTimeout {
+		errs = multierr.Append(
+			errs, fmt.Errorf(
+				`"default_ping_interval": %d pings every %v cannot be sent within "default_ping_timeout" %v`,
+				c.DefaultPingCount, interval, c.DefaultPingTimeout,
+			),
+		)
+	}
+
+	if c.AddressFamily == "" {
+		c.AddressFamily = AddressFamilyAny
+	} else if !isValidAddressFamily(c.AddressFamily) {
+		errs = multierr.Append(errs, fmt.Errorf(`"address_family": %q %s`, c.AddressFamily, "must be one of ip4, ip6 or any"))
+	}
+
+	if c.Source != "" && !isValidSource(c.Source) {
+		errs = multierr.Append(errs, fmt.Errorf(`"source": %q %s`, c.Source, "must be an IP address or an interface name"))
+	}
+
+	if c.RttMode == "" {
+		c.RttMode = RttModeGauge
+	} else if !isValidRttMode(c.RttMode) {
+		errs = multierr.Append(
+			errs, fmt.Errorf(`"rtt_mode": %q %s`, c.RttMode, "must be one of gauge, histogram or exponential_histogram"),
+		)
+	}
+	if !isStrictlyIncreasing(c.RttHistogramBuckets) {
+		errs = multierr.Append(errs, fmt.Errorf(`"rtt_histogram_buckets": %s`, "must be strictly increasing"))
+	}
+	if c.RttHistogramMaxBuckets < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"rtt_histogram_max_buckets": %s`, "cannot be negative"))
+	}
+	if c.RttTrimRatio < 0 || c.RttTrimRatio >= 0.5 {
+		errs = multierr.Append(errs, fmt.Errorf(`"rtt_trim_ratio": %s`, "must be at least 0 and lesser than 0.5"))
+	}
+
+	if c.MaxTargetExpansion < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"max_target_expansion": %s`, "cannot be negative"))
+	}
+
+	if c.Resolver.Server != "" && !isValidDNSServer(c.Resolver.Server) {
+		errs = multierr.Append(
+			errs, fmt.Errorf(`"resolver.server": %q %s`, c.Resolver.Server, "must be an IP address with an optional port"),
+		)
+	}
+	if c.Resolver.Protocol == "" {
+		c.Resolver.Protocol = DNSProtocolUDP
+	} else if c.Resolver.Protocol != DNSProtocolUDP && c.Resolver.Protocol != DNSProtocolTCP {
+		errs = multierr.Append(errs, fmt.Errorf(`"resolver.protocol": %q %s`, c.Resolver.Protocol, "must be one of udp or tcp"))
+	}
+	if c.Resolver.Timeout < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"resolver.timeout": %s`, "cannot be negative"))
+	}
+	if c.Resolver.CacheTTL < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"resolver.cache_ttl": %s`, "cannot be negative"))
+	}
+
+	if c.Mode == "" {
+		c.Mode = ModeBurst
+	} else if c.Mode != ModeBurst && c.Mode != ModeContinuous {
+		errs = multierr.Append(errs, fmt.Errorf(`"mode": %q %s`, c.Mode, "must be one of burst or continuous"))
+	}
+
+	if c.Spread < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"spread": %s`, "cannot be negative"))
+	} else if c.CollectionInterval > 0 && c.Spread >= c.CollectionInterval {
+		errs = multierr.Append(
+			errs, fmt.Errorf(`"spread": %v must be shorter than "collection_interval" %v`, c.Spread, c.CollectionInterval),
+		)
+	}
+
+	if c.StateChanges.LossThreshold < 0 || c.StateChanges.LossThreshold > 1 {
+		errs = multierr.Append(errs, fmt.Errorf(`"state_changes.loss_threshold": %s`, "must be between 0 and 1"))
+	}
+	if c.StateChanges.FailureThreshold == 0 {
+		c.StateChanges.FailureThreshold = 1
+	} else if c.StateChanges.FailureThreshold < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"state_changes.failure_threshold": %s`, "cannot be negative"))
+	}
+	if c.StateChanges.RecoveryThreshold == 0 {
+		c.StateChanges.RecoveryThreshold = 1
+	} else if c.StateChanges.RecoveryThreshold < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"state_changes.recovery_threshold": %s`, "cannot be negative"))
+	}
+
+	if c.MaxConcurrency < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"max_concurrency": %s`, "cannot be negative"))
+	}
+	if c.SeriesExpiryScrapes < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"series_expiry_scrapes": %s`, "cannot be negative"))
+	}
+
+	if len(c.Targets) == 0 && c.TargetsFile == "" {
 		errs = multierr.Append(errs, fmt.Errorf(`"targets": %s`, "cannot be empty or nil"))
 	}
+	if c.TargetsFile != "" && !isValidTargetsFileExt(c.TargetsFile) {
+		errs = multierr.Append(errs, fmt.Errorf(`"targets_file": %q %s`, c.TargetsFile, "must be a .json, .yaml or .yml file"))
+	}
 
+	seen := make(map[string]int, len(c.Targets))
 	for i, target := range c.Targets {
+		parse := parseTarget
+		if target.ResolveMode == ResolveModeSRV {
+			parse = parseSRVName
+		}
+		if name, err := parse(target.Target); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid target %q: %w", i, target.Target, err))
+		} else {
+			c.Targets[i].Target, target.Target = name, name
+			if _, err := expandTarget(target, c.MaxTargetExpansion); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("target #%d %q: %w", i, target.Target, err))
+			}
+		}
+		if !isValidResolveMode(target.ResolveMode) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid resolve_mode %q", i, target.ResolveMode))
+		} else if target.ResolveMode == ResolveModeAll && !isHostName(target.Target) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d: resolve_mode %q requires a host name", i, target.ResolveMode))
+		}
 		if target.PingCount != nil && *target.PingCount < 1 {
 			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid ping_count %d", i, *target.PingCount))
 		}
 		if target.PingTimeout != nil && *target.PingTimeout <= 1*time.Second {
 			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid ping_timeout %v", i, *target.PingTimeout))
 		}
+		if target.AddressFamily != "" && !isValidAddressFamily(target.AddressFamily) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid address_family %q", i, target.AddressFamily))
+		}
+		if target.PacketSize != nil && !isValidPacketSize(*target.PacketSize) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid packet_size %d", i, *target.PacketSize))
+		}
+		if target.TTL != nil && !isValidTTL(*target.TTL) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid ttl %d", i, *target.TTL))
+		}
+		if target.PingInterval != nil && *target.PingInterval <= 0 {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid ping_interval %v", i, *target.PingInterval))
+		}
+		if target.Source != "" && !isValidSource(target.Source) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid source %q", i, target.Source))
+		} else if err := checkSourceFamily(target.source(c.Source), target.addressFamily(c.AddressFamily)); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d: %w", i, err))
+		}
+		if containsSpaces(target.Tag) {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid tag %q: cannot contain spaces", i, target.Tag))
+		}
+		for key := range target.Attributes {
+			if key == "" || slices.Contains(reservedAttributes, key) {
+				errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid attribute %q: %s", i, key, "reserved or empty name"))
+			}
+		}
+		if count, interval, timeout := target.pingSchedule(c); time.Duration(count-1)*interval > timeout {
+			errs = multierr.Append(
+				errs, fmt.Errorf(
+					"target #%d cannot send %d pings every %v within its ping_timeout %v", i, count, interval, timeout,
+				),
+			)
+		}
+		if target.Interval != nil && c.Mode == ModeContinuous {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d: interval cannot be set in %s mode", i, ModeContinuous))
+		} else if target.Interval != nil && *target.Interval <= 0 {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid interval %v", i, *target.Interval))
+		} else if _, _, timeout := target.pingSchedule(c); target.Interval != nil && *target.Interval < timeout {
+			errs = multierr.Append(
+				errs, fmt.Errorf("target #%d: interval %v is shorter than its ping_timeout %v", i, *target.Interval, timeout),
+			)
+		}
+		if target.Spread != nil && *target.Spread < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid spread %v", i, *target.Spread))
+		} else if spread := target.spread(c.Spread); target.Interval != nil && *target.Interval > 0 && spread >= *target.Interval {
+			errs = multierr.Append(
+				errs, fmt.Errorf("target #%d: spread %v must be shorter than its interval %v", i, spread, *target.Interval),
+			)
+		} else if target.Spread != nil && target.Interval == nil && c.CollectionInterval > 0 && spread >= c.CollectionInterval {
+			errs = multierr.Append(
+				errs, fmt.Errorf("target #%d: spread %v must be shorter than collection_interval %v", i, spread, c.CollectionInterval),
+			)
+		}
+		if target.Schedule != "" && target.Schedule != SchedulePeriodic && target.Schedule != SchedulePoisson {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d has invalid schedule %q", i, target.Schedule))
+		} else if target.Schedule == SchedulePoisson && target.Interval == nil {
+			errs = multierr.Append(errs, fmt.Errorf("target #%d: schedule %q requires an interval", i, target.Schedule))
+		}
 
-		// Check for duplicates
-		mu.Lock()
-		if globalTargets[target.Target] {
-			errs = multierr.Append(errs, fmt.Errorf("target #%d with value **%q** is duplicated", i+1, target.Target))
+		// Check for duplicates. The same host may be pinged once per address family.
+		key := target.key(c.AddressFamily)
+		if first, ok := seen[key]; ok {
+			errs = multierr.Append(
+				errs, fmt.Errorf("target #%d with value **%q** is duplicated of target #%d", i+1, target.Target, first+1),
+			)
 		} else {
-			globalTargets[target.Target] = true
+			seen[key] = i
 		}
-		mu.Unlock()
 	}
 
 	return
 }
 
+// addressFamily returns the address family of the target, falling back to the
+// receiver-wide one.
+func (t Target) addressFamily(defaultFamily string) string {
+	if t.AddressFamily != "" {
+		return t.AddressFamily
+	}
+	if defaultFamily != "" {
+		return defaultFamily
+	}
+	return AddressFamilyAny
+}
+
+// key identifies what the target pings, so that equivalent spellings of the
+// same host and address family are recognized as duplicates.
+func (t Target) key(defaultFamily string) string {
+	return normalizeTarget(t.Target) + "/" + t.addressFamily(defaultFamily)
+}
+
+// normalizeTarget returns the canonical form of a target: IP addresses are
+// unmapped and formatted the standard way, prefixes and ranges become the
+// range of addresses they cover, host names are lower-cased and stripped of
+// their trailing dot.
+func normalizeTarget(target string) string {
+	if addr, err := netip.ParseAddr(target); err == nil {
+		return addr.Unmap().String()
+	}
+	if isAddressRange(target) {
+		if r, err := parseAddressRange(target); err == nil {
+			return r.from.String() + "-" + r.to.String()
+		}
+	}
+	return strings.TrimSuffix(strings.ToLower(target), ".")
+}
+
+// tag returns the tag of the target, falling back to the receiver-wide one.
+func (t Target) tag(defaultTag string) string {
+	if t.Tag != "" {
+		return t.Tag
+	}
+	return defaultTag
+}
+
+// source returns the source IP address or interface of the target, falling
+// back to the receiver-wide one.
+func (t Target) source(defaultSource string) string {
+	if t.Source != "" {
+		return t.Source
+	}
+	return defaultSource
+}
+
+// spread returns the spread of the start offsets of the target, falling back to
+// the receiver-wide one.
+func (t Target) spread(defaultSpread time.Duration) time.Duration {
+	if t.Spread != nil {
+		return *t.Spread
+	}
+	return defaultSpread
+}
+
+// partitionTargets splits targets between the ones pinged at every scrape and
+// the ones pinged on their own interval.
+func partitionTargets(targets []Target) (perScrape, scheduled []Target) {
+	for _, target := range targets {
+		if target.Interval != nil {
+			scheduled = append(scheduled, target)
+		} else {
+			perScrape = append(perScrape, target)
+		}
+	}
+	return perScrape, scheduled
+}
+
+// pingSchedule returns the ping count, interval and timeout of the target,
+// falling back to the receiver-wide defaults, and to the prober default for the
+// interval when none is set.
+func (t Target) pingSchedule(c *Config) (count int, interval, timeout time.Duration) {
+	count, interval, timeout = c.DefaultPingCount, cmp.Or(c.DefaultPingInterval, proberPingInterval), c.DefaultPingTimeout
+	if t.PingCount != nil {
+		count = *t.PingCount
+	}
+	if t.PingInterval != nil {
+		interval = *t.PingInterval
+	}
+	if t.PingTimeout != nil {
+		timeout = *t.PingTimeout
+	}
+	return count, interval, timeout
+}
+
+// server returns the address of the DNS server, with the default port when
+// none is set.
+func (c ResolverConfig) server() string {
+	if _, err := netip.ParseAddr(c.Server); err == nil {
+		return net.JoinHostPort(c.Server, "53")
+	}
+	return c.Server
+}
+
+func (c ResolverConfig) protocol() string {
+	if c.Protocol == "" {
+		return DNSProtocolUDP
+	}
+	return c.Protocol
+}
+
+// isValidDNSServer reports whether server is an IP address, with an optional
+// port. Host names are not accepted, as they would need a resolver themselves.
+func isValidDNSServer(server string) bool {
+	if _, err := netip.ParseAddr(server); err == nil {
+		return true
+	}
+	addrPort, err := netip.ParseAddrPort(server)
+	return err == nil && addrPort.Port() != 0
+}
+
+// isValidSource reports whether source is an IP address or could be the name
+// of a network interface. Interfaces are looked up when pinging, as they may
+// come and go while the collector runs.
+func isValidSource(source string) bool {
+	if _, err := netip.ParseAddr(source); err == nil {
+		return true
+	}
+	return len(source) <= maxInterfaceNameLength && !strings.ContainsAny(source, " /:")
+}
+
+// checkSourceFamily rejects source IP addresses that cannot reach the address
+// family of the target.
+func checkSourceFamily(source, addressFamily string) error {
+	addr, err := netip.ParseAddr(source)
+	if err != nil {
+		return nil
+	}
+
+	addr = addr.Unmap()
+	switch {
+	case addressFamily == AddressFamilyIPv4 && !addr.Is4():
+		return fmt.Errorf("source %q is not an IPv4 address", source)
+	case addressFamily == AddressFamilyIPv6 && !addr.Is6():
+		return fmt.Errorf("source %q is not an IPv6 address", source)
+	}
+	return nil
+}
+
+func isValidPacketSize(size int) bool {
+	return size >= minPacketSize && size <= maxPacketSize
+}
+
+func isValidTTL(ttl int) bool {
+	return ttl >= 1 && ttl <= maxTTL
+}
+
+func isValidAddressFamily(family string) bool {
+	switch family {
+	case AddressFamilyIPv4, AddressFamilyIPv6, AddressFamilyAny:
+		return true
+	}
+	return false
+}
+
+func isValidResolveMode(mode string) bool {
+	switch mode {
+	case "", ResolveModeFirst, ResolveModeAll, ResolveModeSRV:
+		return true
+	}
+	return false
+}
+
+func isValidRttMode(mode string) bool {
+	switch mode {
+	case RttModeGauge, RttModeHistogram, RttModeExponentialHistogram:
+		return true
+	}
+	return false
+}
+
+func isValidTargetsFileExt(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func isStrictlyIncreasing(values []float64) bool {
+	for i := 1; i < len(values); i++ {
+		if values[i] <= values[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
 func containsSpaces(s string) bool {
 	for _, r := range s {
 		if r == ' ' {
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/otelcol/otelcoltest"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
 
@@ -42,6 +43,8 @@ func TestLoadConfig(t *testing.T) {
 
 	defaultICMPCheckReceiver.(*Config).DefaultPingCount = 3
 	defaultICMPCheckReceiver.(*Config).DefaultPingTimeout = 5 * time.Second
+	defaultICMPCheckReceiver.(*Config).RttMode = RttModeHistogram
+	defaultICMPCheckReceiver.(*Config).RttHistogramBuckets = []float64{1, 10, 100}
 
 	targets := testDataConfigYamlTargets()
 
@@ -50,7 +53,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, defaultICMPCheckReceiver, r0)
 
 	r1 := cfg.Receivers[component.NewIDWithName(metadata.Type, "custom-5s")].(*Config)
-	assert.Equal(t, testDataConfigYaml5s(), r1)
+	assert.Equal(t, testDataConfigYaml5s(t), r1)
 }
 
 func TestLoadInvalidConfig_NoTargets(t *testing.T) {
@@ -66,6 +69,13 @@ func TestLoadInvalidConfig_NoTargets(t *testing.T) {
 	require.ErrorContains(t, err, "\"default_ping_count\": cannot be lesser than 3")
 	require.ErrorContains(t, err, "\"default_ping_timeout\": cannot be lesser than 5s")
 	require.ErrorContains(t, err, "\"targets\": cannot be empty or nil")
+	require.ErrorContains(t, err, "\"max_concurrency\": cannot be negative")
+	require.ErrorContains(t, err, "\"series_expiry_scrapes\": cannot be negative")
+	require.ErrorContains(t, err, "\"address_family\": \"ip5\" must be one of ip4, ip6 or any")
+	require.ErrorContains(t, err, "\"resolver.server\": \"dns.example.com\" must be an IP address with an optional port")
+	require.ErrorContains(t, err, "\"resolver.protocol\": \"doh\" must be one of udp or tcp")
+	require.ErrorContains(t, err, "\"resolver.timeout\": cannot be negative")
+	require.ErrorContains(t, err, "\"resolver.cache_ttl\": cannot be negative")
 }
 
 func testDataConfigYamlTargets() []Target {
@@ -74,10 +84,17 @@ func testDataConfigYamlTargets() []Target {
 			Target:      "www.amazon.de",
 			PingCount:   func(v int) *int { return &v }(4),
 			PingTimeout: func(v time.Duration) *time.Duration { d := 5 * time.Second; return &d }(5 * time.Second),
+			Interval:    func(v time.Duration) *time.Duration { return &v }(time.Minute),
+			Spread:      func(v time.Duration) *time.Duration { return &v }(30 * time.Second),
+			Schedule:    SchedulePoisson,
 		},
 		{
 			Target: "www.amazon.com",
 		},
+		{
+			Target:        "www.amazon.com",
+			AddressFamily: AddressFamilyIPv6,
+		},
 		{
 			Target: "www.doesnot123exiiiiist.coom",
 		},
@@ -89,24 +106,77 @@ func testDataConfigYamlTargets() []Target {
 			PingTimeout: func(v time.Duration) *time.Duration { d := 2 * time.Second; return &d }(5 * time.Second),
 		},
 		{
-			Target: "8.8.8.8",
+			Target:       "8.8.8.8",
+			Privileged:   func(v bool) *bool { return &v }(true),
+			PacketSize:   func(v int) *int { return &v }(1472),
+			TTL:          func(v int) *int { return &v }(8),
+			PingInterval: func(v time.Duration) *time.Duration { return &v }(200 * time.Millisecond),
+			Source:       "192.0.2.1",
+			Tag:          "dns",
+			Attributes:   map[string]string{"site": "ams1", "sla.tier": "gold"},
+		},
+		{
+			Target:  "10.0.4.0/28",
+			Exclude: []string{"10.0.4.1", "10.0.4.8-10.0.4.9"},
+		},
+		{
+			Target:      "api.example.com",
+			ResolveMode: ResolveModeAll,
+		},
+		{
+			Target:      "_icmp._udp.example.com",
+			ResolveMode: ResolveModeSRV,
 		},
 	}
 	return targets
 }
 
-func testDataConfigYaml5s() *Config {
+func testDataConfigYaml5s(t *testing.T) *Config {
+	metricsBuilderConfig := metadata.DefaultMetricsBuilderConfig()
+	metrics := confmap.NewFromStringMap(map[string]any{
+		"metrics":             map[string]any{"ping.rtt": map[string]any{"enabled": false}},
+		"resource_attributes": map[string]any{"icmpcheck.receiver.name": map[string]any{"enabled": true}},
+	})
+	require.NoError(t, metrics.Unmarshal(&metricsBuilderConfig))
+
 	expectedConfig := &Config{
 		ControllerConfig: scraperhelper.ControllerConfig{
 			CollectionInterval: 5 * time.Second,
 			InitialDelay:       1 * time.Second,
 		},
-		DefaultPingCount:   4,
-		DefaultPingTimeout: 5 * time.Second,
-		Tag:                "fake-custom-5s-tag",
+		MetricsBuilderConfig: metricsBuilderConfig,
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   5 * time.Second,
+		Tag:                  "fake-custom-5s-tag",
+		MaxConcurrency:       2,
+		Privileged:           true,
+		AddressFamily:        AddressFamilyAny,
+		RttMode:              RttModeGauge,
+		Mode:                 ModeContinuous,
+		Spread:               2 * time.Second,
+		Seed:                 42,
+		RttTrimRatio:         defaultRttTrimRatio,
+		MaxTargetExpansion:   defaultMaxTargetExpansion,
+		DefaultPacketSize:    56,
+		DefaultTTL:           32,
+		DefaultPingInterval:  500 * time.Millisecond,
+		Source:               "eth1",
+		TargetsFile:          "testdata/targets/targets.yaml",
+		Resolver: ResolverConfig{
+			Server:   "192.0.2.53",
+			Protocol: DNSProtocolTCP,
+			Timeout:  2 * time.Second,
+			CacheTTL: defaultResolverCacheTTL,
+		},
+		StateChanges: StateChangesConfig{
+			LossThreshold:     0.2,
+			FailureThreshold:  3,
+			RecoveryThreshold: 1,
+		},
+		SeriesExpiryScrapes: 5,
 		Targets: []Target{
 			{
-				Target: "www.bbc.com",
+				Target: "www.cnn.com",
 			},
 		},
 	}
@@ -126,10 +196,30 @@ func TestLoadInvalidConfig_DuplicateTargets(t *testing.T) {
 
 	require.NotNil(t, err)
 
-	require.ErrorContains(t, err, "value **\"localhost1\"** is duplicated")
-	require.ErrorContains(t, err, "value **\"localhost5\"** is duplicated")
-	require.ErrorContains(t, err, "value **\"localhost6\"** is duplicated")
-	require.ErrorContains(t, err, "value **\"localhost7\"** is duplicated")
+	require.ErrorContains(t, err, "value **\"localhost5\"** is duplicated of target #2")
+	require.ErrorContains(t, err, "value **\"localhost7\"** is duplicated of target #3")
+	require.ErrorContains(t, err, "value **\"LocalHost8.\"** is duplicated of target #5")
+	require.ErrorContains(t, err, "value **\"::ffff:192.0.2.1\"** is duplicated of target #8")
+	require.ErrorContains(t, err, "value **\"2001:DB8:0:0::1\"** is duplicated of target #10")
+
+	// Receivers may share targets
+	require.NotContains(t, err.Error(), "localhost1")
+	require.NotContains(t, err.Error(), "localhost6")
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	tests := map[string]string{
+		"www.Example.COM.":  "www.example.com",
+		"localhost":         "localhost",
+		"::ffff:192.0.2.1":  "192.0.2.1",
+		"2001:DB8:0:0::1":   "2001:db8::1",
+		"fe80::1%eth0":      "fe80::1%eth0",
+		"192.0.2.1":         "192.0.2.1",
+		"xn--bcher-kva.de.": "xn--bcher-kva.de",
+	}
+	for target, expected := range tests {
+		assert.Equal(t, expected, normalizeTarget(target), target)
+	}
 }
 
 func TestLoadInvalidConfig_InvalidTags(t *testing.T) {
@@ -144,4 +234,124 @@ func TestLoadInvalidConfig_InvalidTags(t *testing.T) {
 	require.NotNil(t, err)
 
 	require.ErrorContains(t, err, "cannot contain spaces")
+	require.ErrorContains(t, err, "target #1 has invalid tag \"target tag\": cannot contain spaces")
+	require.ErrorContains(t, err, "target #2 has invalid attribute \"net.peer.name\": reserved or empty name")
+}
+
+func TestLoadInvalidConfig_PingOptions(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-ping-options.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "\"default_packet_size\": must be between 24 and 65507")
+	require.ErrorContains(t, err, "\"default_ttl\": must be between 1 and 255")
+	require.ErrorContains(t, err, "\"default_ping_interval\": 3 pings every 3s cannot be sent within \"default_ping_timeout\" 5s")
+	require.ErrorContains(t, err, "target #0 has invalid packet_size 65508")
+	require.ErrorContains(t, err, "target #1 has invalid ttl 0")
+	require.ErrorContains(t, err, "target #2 cannot send 10 pings every 1s within its ping_timeout 5s")
+	require.ErrorContains(t, err, "target #3: source \"10.0.0.1\" is not an IPv6 address")
+	require.ErrorContains(t, err, "target #4 has invalid source \"not an interface\"")
+	require.ErrorContains(t, err, "target #5 has invalid interval -1s")
+	require.ErrorContains(t, err, "target #6: interval 3s is shorter than its ping_timeout 5s")
+	require.ErrorContains(t, err, "\"spread\": 10s must be shorter than \"collection_interval\" 10s")
+	require.ErrorContains(t, err, "target #7 has invalid spread -1s")
+	require.ErrorContains(t, err, "target #8: spread 1m0s must be shorter than its interval 1m0s")
+	require.ErrorContains(t, err, "target #9 has invalid schedule \"random\"")
+	require.ErrorContains(t, err, "target #10: schedule \"poisson\" requires an interval")
+
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-ping-count.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "\"default_ping_interval\": 7 pings every 1s cannot be sent within \"default_ping_timeout\" 5s")
+	require.NotContains(t, err.Error(), "target #0")
+	require.ErrorContains(t, err, "target #1 cannot send 10 pings every 1s within its ping_timeout 8s")
+}
+
+func TestLoadInvalidConfig_RttHistogram(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-rtt-histogram.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "\"rtt_mode\": \"summary\" must be one of gauge, histogram or exponential_histogram")
+	require.ErrorContains(t, err, "\"rtt_histogram_buckets\": must be strictly increasing")
+	require.ErrorContains(t, err, "\"rtt_histogram_max_buckets\": cannot be negative")
+	require.ErrorContains(t, err, "\"rtt_trim_ratio\": must be at least 0 and lesser than 0.5")
+}
+
+func TestLoadInvalidConfig_StateChanges(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-state-changes.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "\"state_changes.loss_threshold\": must be between 0 and 1")
+	require.ErrorContains(t, err, "\"state_changes.failure_threshold\": cannot be negative")
+	require.ErrorContains(t, err, "\"state_changes.recovery_threshold\": cannot be negative")
+}
+
+func TestLoadInvalidConfig_Mode(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-mode.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "target #1: interval cannot be set in continuous mode")
+	require.NotContains(t, err.Error(), "target #0")
+
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-mode-value.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "\"mode\": \"stream\" must be one of burst or continuous")
+}
+
+func TestLoadInvalidConfig_Targets(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-targets.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "target #0 has invalid target \"https://www.example.com/\": must be a host name or an IP address, not a URL")
+	require.ErrorContains(t, err, "target #1 has invalid target \"www.example.com:443\": cannot contain a port")
+	require.ErrorContains(t, err, "target #2 has invalid target \"www.example .com\": cannot contain whitespace")
+	require.ErrorContains(t, err, "target #3 has invalid target \"256.1.1.1\": invalid IP address")
+	require.NotContains(t, err.Error(), "target #4")
+	require.ErrorContains(t, err, "target #5 \"10.0.0.0/16\": expands to more than 1024 addresses, the max_target_expansion")
+	require.ErrorContains(t, err, "target #6 has invalid target \"10.0.4.40-10.0.4.10\": range start 10.0.4.40 is after its end 10.0.4.10")
+	require.ErrorContains(t, err, "target #7 \"10.0.5.0/28\": invalid exclude \"10.0.5.300\"")
+	require.ErrorContains(t, err, "target #8 \"10.0.6.1\": exclude only applies to CIDR prefixes and ranges")
+	require.ErrorContains(t, err, "target #9 has invalid resolve_mode \"every\"")
+	require.ErrorContains(t, err, "target #10: resolve_mode \"all\" requires a host name")
+	require.ErrorContains(t, err, "target #11 has invalid target \"www.example.com\": must be an SRV record name")
+	require.ErrorContains(t, err, "target #12 has invalid target \"_icmp.example.com\": must be an SRV record name")
+}
+
+func TestLoadInvalidConfig_TargetsFile(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[metadata.Type] = factory
+	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "config-invalid-targets-file.yaml"), factories)
+	t.Log(err)
+
+	require.ErrorContains(t, err, "\"targets_file\": \"testdata/targets/targets.txt\" must be a .json, .yaml or .yml file")
+	// Targets may all come from the targets file
+	require.NotContains(t, err.Error(), "\"targets\": cannot be empty or nil")
 }
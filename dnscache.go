@@ -0,0 +1,147 @@
+package icmpreceiver
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// systemResolver adapts a Resolver, which does not report record TTLs, to
+// cache its answers for a fixed time.
+type systemResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+}
+
+func (r systemResolver) lookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, time.Duration, error) {
+	addrs, err := r.resolver.LookupNetIP(ctx, network, host)
+	return addrs, r.ttl, err
+}
+
+func (r systemResolver) lookupSRV(ctx context.Context, name string) ([]*net.SRV, time.Duration, error) {
+	_, records, err := r.resolver.LookupSRV(ctx, "", "", name)
+	return records, r.ttl, err
+}
+
+// cachingResolver is a Resolver keeping answers for as long as their TTL.
+// Failed lookups are not cached, they are tried again on the next scrape.
+type cachingResolver struct {
+	resolver ttlResolver
+	// timeout bounds each lookup, when positive.
+	timeout time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	addrs   []netip.Addr
+	records []*net.SRV
+	expires time.Time
+}
+
+func newCachingResolver(resolver ttlResolver, timeout time.Duration) *cachingResolver {
+	return &cachingResolver{
+		resolver: resolver,
+		timeout:  timeout,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+	}
+}
+
+// newResolver returns the resolver described by cfg: the system resolver, or
+// the configured DNS server, behind a cache.
+func newResolver(cfg ResolverConfig) *cachingResolver {
+	if cfg.Server != "" {
+		return newCachingResolver(&dnsClient{server: cfg.server(), protocol: cfg.protocol()}, cfg.Timeout)
+	}
+
+	resolver := net.DefaultResolver
+	if cfg.protocol() == DNSProtocolTCP {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, _, address string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, DNSProtocolTCP, address)
+			},
+		}
+	}
+	return newCachingResolver(systemResolver{resolver: resolver, ttl: cfg.CacheTTL}, cfg.Timeout)
+}
+
+func (r *cachingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	key := network + "/" + strings.ToLower(host)
+	if entry, ok := r.get(key); ok {
+		return entry.addrs, nil
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	addrs, ttl, err := r.resolver.lookupNetIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	r.put(key, cacheEntry{addrs: addrs}, ttl)
+	return addrs, nil
+}
+
+func (r *cachingResolver) LookupSRV(ctx context.Context, _, _, name string) (string, []*net.SRV, error) {
+	key := "srv/" + strings.ToLower(name)
+	if entry, ok := r.get(key); ok {
+		return name, entry.records, nil
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	records, ttl, err := r.resolver.lookupSRV(ctx, name)
+	if err != nil {
+		return "", nil, err
+	}
+	r.put(key, cacheEntry{records: records}, ttl)
+	return name, records, nil
+}
+
+func (r *cachingResolver) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+func (r *cachingResolver) get(key string) (cacheEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if !r.now().Before(entry.expires) {
+		delete(r.entries, key)
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (r *cachingResolver) put(key string, entry cacheEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	// Forget expired entries, e.g. of targets removed from the targets file.
+	for k, e := range r.entries {
+		if !now.Before(e.expires) {
+			delete(r.entries, k)
+		}
+	}
+	entry.expires = now.Add(ttl)
+	r.entries[key] = entry
+}
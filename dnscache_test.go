@@ -0,0 +1,123 @@
+package icmpreceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// countingResolver is a ttlResolver counting its lookups.
+type countingResolver struct {
+	ttl     time.Duration
+	err     error
+	lookups int
+	// deadline is the deadline of the context of the last lookup.
+	deadline time.Time
+}
+
+func (r *countingResolver) lookupNetIP(ctx context.Context, _, _ string) ([]netip.Addr, time.Duration, error) {
+	r.lookups++
+	r.deadline, _ = ctx.Deadline()
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	return []netip.Addr{netip.MustParseAddr("192.0.2.1")}, r.ttl, nil
+}
+
+func (r *countingResolver) lookupSRV(context.Context, string) ([]*net.SRV, time.Duration, error) {
+	r.lookups++
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	return []*net.SRV{{Target: "host.example.com."}}, r.ttl, nil
+}
+
+func TestCachingResolver(t *testing.T) {
+	counting := &countingResolver{ttl: time.Minute}
+	resolver := newCachingResolver(counting, 0)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resolver.now = func() time.Time { return now }
+
+	for range 3 {
+		addrs, err := resolver.LookupNetIP(context.Background(), "ip", "www.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.1")}, addrs)
+	}
+	assert.Equal(t, 1, counting.lookups, "answers are cached for their TTL")
+
+	// Names are cached per address family, case-insensitively
+	_, err := resolver.LookupNetIP(context.Background(), "ip6", "www.example.com")
+	require.NoError(t, err)
+	_, err = resolver.LookupNetIP(context.Background(), "ip", "WWW.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, counting.lookups)
+
+	now = now.Add(time.Minute)
+	_, err = resolver.LookupNetIP(context.Background(), "ip", "www.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 3, counting.lookups, "expired answers are looked up again")
+
+	for range 2 {
+		_, records, err := resolver.LookupSRV(context.Background(), "", "", "_icmp._udp.example.com")
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+	}
+	assert.Equal(t, 4, counting.lookups)
+}
+
+func TestCachingResolverDoesNotCacheErrors(t *testing.T) {
+	counting := &countingResolver{ttl: time.Minute, err: errors.New("server misbehaving")}
+	resolver := newCachingResolver(counting, 0)
+
+	for range 2 {
+		_, err := resolver.LookupNetIP(context.Background(), "ip", "www.example.com")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, counting.lookups)
+}
+
+func TestCachingResolverWithoutTTL(t *testing.T) {
+	counting := &countingResolver{}
+	resolver := newCachingResolver(counting, 0)
+
+	for range 2 {
+		_, err := resolver.LookupNetIP(context.Background(), "ip", "www.example.com")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, counting.lookups, "a TTL of 0 disables caching")
+}
+
+func TestCachingResolverTimeout(t *testing.T) {
+	counting := &countingResolver{}
+	resolver := newCachingResolver(counting, time.Second)
+
+	_, err := resolver.LookupNetIP(context.Background(), "ip", "www.example.com")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Second), counting.deadline, time.Second)
+}
+
+func TestNewResolver(t *testing.T) {
+	server := newStubDNSServer(
+		t, map[string][]dnsmessage.Resource{"www.example.test.": {withTTL(aRecord("192.0.2.1"), 60)}},
+	)
+
+	resolver := newResolver(ResolverConfig{Server: server.conn.LocalAddr().String(), Timeout: time.Second})
+	for range 2 {
+		addrs, err := resolver.LookupNetIP(context.Background(), "ip4", "www.example.test")
+		require.NoError(t, err)
+		assert.Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.1")}, addrs)
+	}
+	assert.Equal(t, []string{"TypeA www.example.test."}, server.receivedQueries())
+
+	system, ok := newResolver(ResolverConfig{CacheTTL: time.Minute}).resolver.(systemResolver)
+	require.True(t, ok)
+	assert.Equal(t, net.DefaultResolver, system.resolver)
+	assert.Equal(t, time.Minute, system.ttl)
+}
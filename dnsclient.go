@@ -0,0 +1,230 @@
+package icmpreceiver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	DNSProtocolUDP = "udp"
+	DNSProtocolTCP = "tcp"
+
+	// maxUDPMessageSize is the largest DNS message read over UDP. Longer
+	// answers are truncated by the server and queried again over TCP.
+	maxUDPMessageSize = 1232
+)
+
+// ttlResolver is a resolver that reports how long its answers may be cached.
+type ttlResolver interface {
+	lookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, time.Duration, error)
+	lookupSRV(ctx context.Context, name string) ([]*net.SRV, time.Duration, error)
+}
+
+// dnsClient queries a single DNS server directly, which tells the TTL of the
+// records, unlike the resolver of the standard library. Names are queried as
+// fully qualified, without search domains.
+type dnsClient struct {
+	server   string
+	protocol string
+}
+
+func (c *dnsClient) lookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, time.Duration, error) {
+	var types []dnsmessage.Type
+	switch network {
+	case "ip4":
+		types = []dnsmessage.Type{dnsmessage.TypeA}
+	case "ip6":
+		types = []dnsmessage.Type{dnsmessage.TypeAAAA}
+	default:
+		types = []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+	}
+
+	var (
+		addrs    []netip.Addr
+		ttls     []time.Duration
+		firstErr error
+	)
+	for _, qtype := range types {
+		answers, ttl, err := c.query(ctx, host, qtype)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, answer := range answers {
+			switch body := answer.(type) {
+			case *dnsmessage.AResource:
+				addrs = append(addrs, netip.AddrFrom4(body.A))
+			case *dnsmessage.AAAAResource:
+				addrs = append(addrs, netip.AddrFrom16(body.AAAA))
+			}
+		}
+		if len(answers) > 0 {
+			ttls = append(ttls, ttl)
+		}
+	}
+
+	switch {
+	case len(addrs) > 0:
+		return addrs, slices.Min(ttls), nil
+	case firstErr != nil:
+		return nil, 0, firstErr
+	default:
+		return nil, 0, &net.DNSError{Err: "no such host", Name: host, Server: c.server, IsNotFound: true}
+	}
+}
+
+func (c *dnsClient) lookupSRV(ctx context.Context, name string) ([]*net.SRV, time.Duration, error) {
+	answers, ttl, err := c.query(ctx, name, dnsmessage.TypeSRV)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var records []*net.SRV
+	for _, answer := range answers {
+		if body, ok := answer.(*dnsmessage.SRVResource); ok {
+			records = append(records, &net.SRV{
+				Target:   body.Target.String(),
+				Port:     body.Port,
+				Priority: body.Priority,
+				Weight:   body.Weight,
+			})
+		}
+	}
+	if len(records) == 0 {
+		return nil, 0, &net.DNSError{Err: "no such host", Name: name, Server: c.server, IsNotFound: true}
+	}
+	slices.SortStableFunc(records, func(a, b *net.SRV) int { return int(a.Priority) - int(b.Priority) })
+	return records, ttl, nil
+}
+
+// query asks the server for the records of the given type, and returns the
+// answers along with the lowest TTL among them.
+func (c *dnsClient) query(ctx context.Context, name string, qtype dnsmessage.Type) ([]dnsmessage.ResourceBody, time.Duration, error) {
+	fqdn := name
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+	qname, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: err.Error(), Name: name, Server: c.server}
+	}
+
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: uint16(rand.Uint32()), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reply, err := c.exchange(ctx, c.protocol, packed, query.ID)
+	if err == nil && reply.Truncated && c.protocol == DNSProtocolUDP {
+		reply, err = c.exchange(ctx, DNSProtocolTCP, packed, query.ID)
+	}
+	if err != nil {
+		dnsErr := &net.DNSError{Err: err.Error(), Name: name, Server: c.server}
+		var netErr net.Error
+		dnsErr.IsTimeout = errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+		return nil, 0, dnsErr
+	}
+
+	switch reply.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, 0, &net.DNSError{Err: "no such host", Name: name, Server: c.server, IsNotFound: true}
+	case dnsmessage.RCodeServerFailure:
+		return nil, 0, &net.DNSError{Err: "server misbehaving", Name: name, Server: c.server, IsTemporary: true}
+	default:
+		return nil, 0, &net.DNSError{Err: fmt.Sprintf("server answered %v", reply.RCode), Name: name, Server: c.server}
+	}
+
+	var (
+		answers []dnsmessage.ResourceBody
+		ttl     time.Duration
+	)
+	for i, answer := range reply.Answers {
+		// CNAME records of the chain leading to the answers count for the TTL.
+		if answerTTL := time.Duration(answer.Header.TTL) * time.Second; i == 0 || answerTTL < ttl {
+			ttl = answerTTL
+		}
+		if answer.Header.Type == qtype {
+			answers = append(answers, answer.Body)
+		}
+	}
+	return answers, ttl, nil
+}
+
+// exchange sends a packed query to the server and reads its reply.
+func (c *dnsClient) exchange(ctx context.Context, protocol string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, protocol, c.server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if protocol == DNSProtocolTCP {
+		return exchangeTCP(conn, query)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, maxUDPMessageSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var reply dnsmessage.Message
+		// Ignore stray datagrams, e.g. late replies to another query.
+		if err := reply.Unpack(buf[:n]); err == nil && reply.Response && reply.ID == id {
+			return &reply, nil
+		}
+	}
+}
+
+// exchangeTCP sends a query over a TCP connection, where messages are
+// prefixed with their length.
+func exchangeTCP(conn net.Conn, query []byte) (*dnsmessage.Message, error) {
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+	if _, err := conn.Write(append(framed, query...)); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+
+	var reply dnsmessage.Message
+	if err := reply.Unpack(buf); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
@@ -0,0 +1,90 @@
+package icmpreceiver
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestDNSClient(t *testing.T) {
+	server := newStubDNSServer(
+		t, map[string][]dnsmessage.Resource{
+			"api.example.test.": {
+				withTTL(aRecord("192.0.2.10"), 300), withTTL(aRecord("192.0.2.11"), 120), withTTL(aaaaRecord("2001:db8::10"), 60),
+			},
+			"v4only.example.test.":     {withTTL(aRecord("192.0.2.20"), 30)},
+			"_icmp._udp.example.test.": {withTTL(srvRecord("api.example.test."), 90)},
+		},
+	)
+
+	for _, protocol := range []string{DNSProtocolUDP, DNSProtocolTCP} {
+		t.Run(protocol, func(t *testing.T) {
+			address := server.conn.LocalAddr().String()
+			if protocol == DNSProtocolTCP {
+				address = server.listener.Addr().String()
+			}
+			client := &dnsClient{server: address, protocol: protocol}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			addrs, ttl, err := client.lookupNetIP(ctx, "ip", "api.example.test")
+			require.NoError(t, err)
+			assert.Equal(
+				t, []netip.Addr{
+					netip.MustParseAddr("192.0.2.10"), netip.MustParseAddr("192.0.2.11"), netip.MustParseAddr("2001:db8::10"),
+				}, addrs,
+			)
+			assert.Equal(t, 60*time.Second, ttl, "the lowest TTL of the records is kept")
+
+			addrs, ttl, err = client.lookupNetIP(ctx, "ip4", "api.example.test.")
+			require.NoError(t, err)
+			assert.Len(t, addrs, 2)
+			assert.Equal(t, 120*time.Second, ttl)
+
+			// Names without AAAA records are found over any address family
+			addrs, ttl, err = client.lookupNetIP(ctx, "ip", "v4only.example.test")
+			require.NoError(t, err)
+			assert.Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.20")}, addrs)
+			assert.Equal(t, 30*time.Second, ttl)
+
+			_, _, err = client.lookupNetIP(ctx, "ip6", "v4only.example.test")
+			var dnsErr *net.DNSError
+			require.ErrorAs(t, err, &dnsErr)
+			assert.True(t, dnsErr.IsNotFound)
+
+			_, _, err = client.lookupNetIP(ctx, "ip", "missing.example.test")
+			require.ErrorAs(t, err, &dnsErr)
+			assert.True(t, dnsErr.IsNotFound)
+			assert.Equal(t, address, dnsErr.Server)
+
+			records, ttl, err := client.lookupSRV(ctx, "_icmp._udp.example.test")
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			assert.Equal(t, "api.example.test.", records[0].Target)
+			assert.Equal(t, 90*time.Second, ttl)
+		})
+	}
+}
+
+func TestDNSClientTimeout(t *testing.T) {
+	// A server that never answers
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := &dnsClient{server: conn.LocalAddr().String(), protocol: DNSProtocolUDP}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err = client.lookupNetIP(ctx, "ip4", "api.example.test")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	assert.True(t, dnsErr.IsTimeout)
+}
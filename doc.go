@@ -0,0 +1,5 @@
+//go:generate go run go.opentelemetry.io/collector/cmd/mdatagen@v0.143.0 metadata.yaml
+
+// Package icmpreceiver implements a receiver that pings a list of targets and
+// reports round-trip times and packet loss as metrics.
+package icmpreceiver
@@ -0,0 +1,182 @@
+package icmpreceiver
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// defaultMaxTargetExpansion is the default number of addresses a CIDR prefix
+// or a range target may expand into.
+const defaultMaxTargetExpansion = 1024
+
+// addressRange is an inclusive range of IP addresses of the same family.
+type addressRange struct {
+	from, to netip.Addr
+}
+
+func (r addressRange) contains(addr netip.Addr) bool {
+	return r.from.Compare(addr) <= 0 && addr.Compare(r.to) <= 0
+}
+
+// isAddressRange reports whether target is a CIDR prefix or a range of
+// addresses, as opposed to a single IP address or a host name.
+func isAddressRange(target string) bool {
+	if strings.Contains(target, "/") {
+		return true
+	}
+	from, to, ok := strings.Cut(target, "-")
+	if !ok {
+		return false
+	}
+	_, fromErr := netip.ParseAddr(from)
+	_, toErr := netip.ParseAddr(to)
+	return fromErr == nil && toErr == nil
+}
+
+// parseAddressRange parses an IP address, a CIDR prefix or a range of
+// addresses, e.g. 10.0.4.10-10.0.4.40. The network and broadcast addresses of
+// IPv4 prefixes are left out, unless the prefix is a /31 or a /32.
+func parseAddressRange(s string) (addressRange, error) {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		addr = addr.Unmap()
+		return addressRange{from: addr, to: addr}, nil
+	}
+
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return addressRange{}, fmt.Errorf("invalid CIDR prefix: %w", err)
+		}
+		prefix = prefix.Masked()
+
+		r := addressRange{from: prefix.Addr(), to: lastAddr(prefix)}
+		if prefix.Addr().Is4() && prefix.Bits() <= 30 {
+			r.from, r.to = r.from.Next(), r.to.Prev()
+		}
+		return r, nil
+	}
+
+	fromStr, toStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return addressRange{}, errors.New("must be an IP address, a CIDR prefix or a range of addresses")
+	}
+	from, err := netip.ParseAddr(fromStr)
+	if err != nil {
+		return addressRange{}, fmt.Errorf("invalid range start: %w", err)
+	}
+	to, err := netip.ParseAddr(toStr)
+	if err != nil {
+		return addressRange{}, fmt.Errorf("invalid range end: %w", err)
+	}
+
+	from, to = from.Unmap(), to.Unmap()
+	switch {
+	case from.BitLen() != to.BitLen():
+		return addressRange{}, errors.New("range mixes IPv4 and IPv6 addresses")
+	case from.Compare(to) > 0:
+		return addressRange{}, fmt.Errorf("range start %v is after its end %v", from, to)
+	}
+	return addressRange{from: from, to: to}, nil
+}
+
+// lastAddr returns the last address of a masked prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	if prefix.Addr().Is4() {
+		a := prefix.Addr().As4()
+		for i := prefix.Bits(); i < 32; i++ {
+			a[i/8] |= 1 << (7 - i%8)
+		}
+		return netip.AddrFrom4(a)
+	}
+
+	a := prefix.Addr().As16()
+	for i := prefix.Bits(); i < 128; i++ {
+		a[i/8] |= 1 << (7 - i%8)
+	}
+	return netip.AddrFrom16(a).WithZone(prefix.Addr().Zone())
+}
+
+// expandTarget returns the targets to ping for a configured target: itself,
+// or one target per address of its prefix or range that is not excluded.
+// Expanded targets remember the prefix they come from. Prefixes and ranges of
+// more than limit addresses are rejected, before exclusions are applied.
+func expandTarget(target Target, limit int) ([]Target, error) {
+	if !isAddressRange(target.Target) {
+		if len(target.Exclude) > 0 {
+			return nil, errors.New("exclude only applies to CIDR prefixes and ranges")
+		}
+		return []Target{target}, nil
+	}
+
+	r, err := parseAddressRange(target.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes := make([]addressRange, 0, len(target.Exclude))
+	for _, exclude := range target.Exclude {
+		excluded, err := parseAddressRange(exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude %q: %w", exclude, err)
+		}
+		excludes = append(excludes, excluded)
+	}
+
+	if limit <= 0 {
+		limit = defaultMaxTargetExpansion
+	}
+
+	var (
+		targets []Target
+		count   int
+	)
+	for addr := r.from; addr.IsValid() && addr.Compare(r.to) <= 0; addr = addr.Next() {
+		if count++; count > limit {
+			return nil, fmt.Errorf("expands to more than %d addresses, the max_target_expansion", limit)
+		}
+		if isExcluded(addr, excludes) {
+			continue
+		}
+
+		expanded := target
+		expanded.Target = addr.String()
+		expanded.Exclude = nil
+		expanded.prefix = target.Target
+		targets = append(targets, expanded)
+	}
+	return targets, nil
+}
+
+func isExcluded(addr netip.Addr, excludes []addressRange) bool {
+	for _, r := range excludes {
+		if r.contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandTargets expands all prefixes and ranges of targets. Expanded addresses
+// already covered by a previous target are skipped, so overlapping targets
+// ping them once, with the options of the first one.
+func expandTargets(targets []Target, limit int, defaultFamily string) ([]Target, error) {
+	var expanded []Target
+	seen := make(map[string]bool, len(targets))
+	for i, target := range targets {
+		ts, err := expandTarget(target, limit)
+		if err != nil {
+			return nil, fmt.Errorf("target #%d %q: %w", i, target.Target, err)
+		}
+		for _, t := range ts {
+			key := t.key(defaultFamily)
+			if seen[key] && t.prefix != "" {
+				continue
+			}
+			seen[key] = true
+			expanded = append(expanded, t)
+		}
+	}
+	return expanded, nil
+}
@@ -0,0 +1,121 @@
+package icmpreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  Target
+		limit   int
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "host name",
+			target: Target{Target: "www.example.com"},
+			want:   []string{"www.example.com"},
+		},
+		{
+			name:   "IPv4 prefix without network and broadcast addresses",
+			target: Target{Target: "10.0.4.0/29"},
+			want:   []string{"10.0.4.1", "10.0.4.2", "10.0.4.3", "10.0.4.4", "10.0.4.5", "10.0.4.6"},
+		},
+		{
+			name:   "unmasked IPv4 prefix",
+			target: Target{Target: "10.0.4.7/30"},
+			want:   []string{"10.0.4.5", "10.0.4.6"},
+		},
+		{
+			name:   "IPv4 /31",
+			target: Target{Target: "10.0.4.0/31"},
+			want:   []string{"10.0.4.0", "10.0.4.1"},
+		},
+		{
+			name:   "IPv6 prefix",
+			target: Target{Target: "2001:db8::/126"},
+			want:   []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"},
+		},
+		{
+			name:   "range with exclusions",
+			target: Target{Target: "10.0.4.10-10.0.4.16", Exclude: []string{"10.0.4.11", "10.0.4.13-10.0.4.14", "10.0.4.16/32"}},
+			want:   []string{"10.0.4.10", "10.0.4.12", "10.0.4.15"},
+		},
+		{
+			name:   "range within the limit",
+			target: Target{Target: "10.0.4.1-10.0.4.4"},
+			limit:  4,
+			want:   []string{"10.0.4.1", "10.0.4.2", "10.0.4.3", "10.0.4.4"},
+		},
+		{
+			name:    "range over the limit",
+			target:  Target{Target: "10.0.4.1-10.0.4.5"},
+			limit:   4,
+			wantErr: "expands to more than 4 addresses",
+		},
+		{
+			name:    "IPv6 prefix over the default limit",
+			target:  Target{Target: "2001:db8::/64"},
+			wantErr: "expands to more than 1024 addresses",
+		},
+		{
+			name:    "mixed range",
+			target:  Target{Target: "10.0.4.1-2001:db8::1"},
+			wantErr: "range mixes IPv4 and IPv6 addresses",
+		},
+		{
+			name:    "invalid exclude",
+			target:  Target{Target: "10.0.4.0/28", Exclude: []string{"www.example.com"}},
+			wantErr: `invalid exclude "www.example.com"`,
+		},
+		{
+			name:    "exclude without prefix",
+			target:  Target{Target: "10.0.4.1", Exclude: []string{"10.0.4.1"}},
+			wantErr: "exclude only applies to CIDR prefixes and ranges",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				targets, err := expandTarget(tt.target, tt.limit)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+
+				var got []string
+				for _, target := range targets {
+					got = append(got, target.Target)
+					assert.Empty(t, target.Exclude)
+					if isAddressRange(tt.target.Target) {
+						assert.Equal(t, tt.target.Target, target.prefix)
+					}
+				}
+				assert.Equal(t, tt.want, got)
+			},
+		)
+	}
+}
+
+func TestExpandTargetsSkipsOverlaps(t *testing.T) {
+	count := 3
+	targets, err := expandTargets(
+		[]Target{
+			{Target: "10.0.4.2", PingCount: &count},
+			{Target: "10.0.4.0/30"},
+			{Target: "10.0.4.1-10.0.4.3"},
+		}, 0, AddressFamilyAny,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, targets, 3)
+	assert.Equal(t, Target{Target: "10.0.4.2", PingCount: &count}, targets[0])
+	assert.Equal(t, Target{Target: "10.0.4.1", prefix: "10.0.4.0/30"}, targets[1])
+	assert.Equal(t, Target{Target: "10.0.4.3", prefix: "10.0.4.1-10.0.4.3"}, targets[2])
+}
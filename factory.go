@@ -3,23 +3,64 @@ package icmpreceiver
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/scraper"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
+	"go.uber.org/multierr"
 
 	"github.com/supersun/otel-icmp-receiver/internal/metadata"
 )
 
+const (
+	defaultMaxConcurrency   = 10
+	defaultSeriesExpiry     = 10
+	defaultResolverTimeout  = 5 * time.Second
+	defaultResolverCacheTTL = 30 * time.Second
+)
+
 var errConfigNotPingReceiver = fmt.Errorf("config is not valid for the '%s' receiver", metadata.Type)
 
-func NewFactory() receiver.Factory {
+// FactoryOption customizes the receivers created by NewFactory.
+type FactoryOption func(*factoryOptions)
+
+type factoryOptions struct {
+	prober   Prober
+	resolver Resolver
+}
+
+// WithProber replaces the default pro-bing based Prober, e.g. with a fake in tests.
+func WithProber(prober Prober) FactoryOption {
+	return func(o *factoryOptions) {
+		o.prober = prober
+	}
+}
+
+// WithResolver replaces the resolver of host names and its cache, e.g. with a
+// stub in tests.
+func WithResolver(resolver Resolver) FactoryOption {
+	return func(o *factoryOptions) {
+		o.resolver = resolver
+	}
+}
+
+func NewFactory(opts ...FactoryOption) receiver.Factory {
+	fopts := factoryOptions{prober: newProBingProber()}
+	for _, opt := range opts {
+		opt(&fopts)
+	}
+
+	registry, receivers := newTargetRegistry(), newSharedReceivers()
 	return receiver.NewFactory(
 		metadata.Type,
 		createDefaultConfig,
-		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithMetrics(createMetricsReceiver(fopts, registry, receivers), metadata.MetricsStability),
+		receiver.WithLogs(createLogsReceiver(fopts, registry, receivers), metadata.LogsStability),
 	)
 }
 
@@ -27,32 +68,105 @@ func createDefaultConfig() component.Config {
 	cfg := scraperhelper.NewDefaultControllerConfig()
 
 	return &Config{
-		ControllerConfig: cfg,
-		Targets:          []Target{},
-		Tag:              TagNotSet,
+		ControllerConfig:     cfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{},
+		Tag:                  TagNotSet,
+		MaxConcurrency:       defaultMaxConcurrency,
+		AddressFamily:        AddressFamilyAny,
+		RttMode:              RttModeGauge,
+		Mode:                 ModeBurst,
+		RttTrimRatio:         defaultRttTrimRatio,
+		MaxTargetExpansion:   defaultMaxTargetExpansion,
+		Resolver: ResolverConfig{
+			Protocol: DNSProtocolUDP,
+			Timeout:  defaultResolverTimeout,
+			CacheTTL: defaultResolverCacheTTL,
+		},
+		StateChanges: StateChangesConfig{
+			LossThreshold:     defaultLossThreshold,
+			FailureThreshold:  1,
+			RecoveryThreshold: 1,
+		},
+		SeriesExpiryScrapes: defaultSeriesExpiry,
 	}
 }
 
 func createMetricsReceiver(
-	_ context.Context,
-	set receiver.Settings,
-	cfg component.Config,
-	nextConsumer consumer.Metrics,
-) (receiver.Metrics, error) {
-	receiverCfg, ok := cfg.(*Config)
-	if !ok {
-		return nil, errConfigNotPingReceiver
+	fopts factoryOptions, registry *targetRegistry, receivers *sharedReceivers,
+) receiver.CreateMetricsFunc {
+	return func(
+		_ context.Context,
+		set receiver.Settings,
+		cfg component.Config,
+		nextConsumer consumer.Metrics,
+	) (receiver.Metrics, error) {
+		receiverCfg, ok := cfg.(*Config)
+		if !ok {
+			return nil, errConfigNotPingReceiver
+		}
+
+		r, err := receivers.getOrCreate(receiverCfg, func() (*icmpReceiver, error) {
+			return newICMPReceiver(set, receiverCfg, fopts, registry)
+		})
+		if err != nil {
+			return nil, err
+		}
+		r.metrics = nextConsumer
+		return r, nil
 	}
+}
+
+func createLogsReceiver(
+	fopts factoryOptions, registry *targetRegistry, receivers *sharedReceivers,
+) receiver.CreateLogsFunc {
+	return func(
+		_ context.Context,
+		set receiver.Settings,
+		cfg component.Config,
+		nextConsumer consumer.Logs,
+	) (receiver.Logs, error) {
+		receiverCfg, ok := cfg.(*Config)
+		if !ok {
+			return nil, errConfigNotPingReceiver
+		}
+
+		r, err := receivers.getOrCreate(receiverCfg, func() (*icmpReceiver, error) {
+			return newICMPReceiver(set, receiverCfg, fopts, registry)
+		})
+		if err != nil {
+			return nil, err
+		}
+		r.scraper.logs = nextConsumer
+		return r, nil
+	}
+}
+
+// newICMPReceiver creates the scraper of the configuration and the controller
+// that runs it. Its metrics are dropped until a metrics pipeline uses the
+// receiver.
+func newICMPReceiver(
+	set receiver.Settings,
+	receiverCfg *Config,
+	fopts factoryOptions,
+	registry *targetRegistry,
+) (*icmpReceiver, error) {
 	set.Logger.Info("about creating new icmp check receiver - newPingScraper")
 
 	opts := []scraperhelper.ControllerOption{}
 
-	icmpScraper, err := newPingScraper(receiverCfg, set)
+	icmpScraper, err := newPingScraper(receiverCfg, set, fopts.prober)
 	if err != nil {
 		return nil, err
 	}
+	if fopts.resolver != nil {
+		icmpScraper.resolver = fopts.resolver
+	}
+	if receiverCfg.ExclusiveTargets {
+		icmpScraper.registry = registry
+	}
 
-	scp, err := scraper.NewMetrics(icmpScraper.Scrape)
+	scp, err := scraper.NewMetrics(icmpScraper.Scrape, scraper.WithStart(icmpScraper.start))
 	if err != nil {
 		return nil, err
 	}
@@ -61,5 +175,89 @@ func createMetricsReceiver(
 
 	set.Logger.Info("about creating new icmp check receiver - scraperhelper.NewMetricsController")
 
-	return scraperhelper.NewMetricsController(&receiverCfg.ControllerConfig, set, nextConsumer, opts...)
+	r := &icmpReceiver{scraper: icmpScraper}
+	nextConsumer, err := consumer.NewMetrics(r.consumeMetrics)
+	if err != nil {
+		return nil, err
+	}
+	r.controller, err = scraperhelper.NewMetricsController(&receiverCfg.ControllerConfig, set, nextConsumer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// icmpReceiver is the receiver of a configuration, shared by its metrics and
+// logs pipelines so that its targets are pinged once. It stops the scraper's
+// running pings before shutting down the controller, which would otherwise
+// block until the current scrape completes.
+type icmpReceiver struct {
+	scraper    *pingScraper
+	controller receiver.Metrics
+	// metrics is the consumer of the metrics pipeline, nil when the receiver
+	// is only used by a logs pipeline.
+	metrics consumer.Metrics
+	// release removes the receiver from the ones shared by the factory.
+	release func()
+
+	startOnce    sync.Once
+	startErr     error
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+func (r *icmpReceiver) Start(ctx context.Context, host component.Host) error {
+	r.startOnce.Do(func() {
+		r.startErr = r.controller.Start(ctx, host)
+	})
+	return r.startErr
+}
+
+func (r *icmpReceiver) Shutdown(ctx context.Context) error {
+	r.shutdownOnce.Do(func() {
+		if r.release != nil {
+			r.release()
+		}
+		r.shutdownErr = multierr.Append(r.scraper.shutdown(ctx), r.controller.Shutdown(ctx))
+	})
+	return r.shutdownErr
+}
+
+func (r *icmpReceiver) consumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if r.metrics == nil {
+		return nil
+	}
+	return r.metrics.ConsumeMetrics(ctx, md)
+}
+
+// sharedReceivers holds the receivers created by a factory until they are
+// shut down, by configuration.
+type sharedReceivers struct {
+	mu        sync.Mutex
+	receivers map[*Config]*icmpReceiver
+}
+
+func newSharedReceivers() *sharedReceivers {
+	return &sharedReceivers{receivers: make(map[*Config]*icmpReceiver)}
+}
+
+// getOrCreate returns the receiver of cfg, creating it the first time.
+func (s *sharedReceivers) getOrCreate(cfg *Config, create func() (*icmpReceiver, error)) (*icmpReceiver, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.receivers[cfg]; ok {
+		return r, nil
+	}
+	r, err := create()
+	if err != nil {
+		return nil, err
+	}
+	r.release = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.receivers, cfg)
+	}
+	s.receivers[cfg] = r
+	return r, nil
 }
@@ -4,23 +4,18 @@ import (
 	"context"
 	"testing"
 
-	"go.uber.org/goleak"
-
 	"github.com/supersun/otel-icmp-receiver/internal/metadata"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 )
 
-func TestMain(m *testing.M) {
-	goleak.VerifyTestMain(m)
-}
-
 func TestCreateMetrics(t *testing.T) {
 	t.Run(
 		"Nil config gives error", func(t *testing.T) {
-			recv, err := createMetricsReceiver(
+			recv, err := createMetricsReceiver(factoryOptions{prober: newFakeProber(nil)}, newTargetRegistry(), newSharedReceivers())(
 				context.Background(),
 				receivertest.NewNopSettings(metadata.Type),
 				nil,
@@ -35,7 +30,7 @@ func TestCreateMetrics(t *testing.T) {
 
 	t.Run(
 		"Metrics receiver is created with default config", func(t *testing.T) {
-			recv, err := createMetricsReceiver(
+			recv, err := createMetricsReceiver(factoryOptions{prober: newFakeProber(nil)}, newTargetRegistry(), newSharedReceivers())(
 				context.Background(),
 				receivertest.NewNopSettings(metadata.Type),
 				createDefaultConfig(),
@@ -51,3 +46,46 @@ func TestCreateMetrics(t *testing.T) {
 		},
 	)
 }
+
+func TestNewFactoryWithProber(t *testing.T) {
+	prober := newFakeProber(nil)
+	factory := NewFactory(WithProber(prober))
+
+	recv, err := factory.CreateMetrics(
+		context.Background(),
+		receivertest.NewNopSettings(metadata.Type),
+		factory.CreateDefaultConfig(),
+		&consumertest.MetricsSink{},
+	)
+	require.NoError(t, err)
+
+	require.Same(t, prober, recv.(*icmpReceiver).scraper.prober)
+	require.NoError(t, recv.Shutdown(context.Background()))
+}
+
+func TestNewFactoryWithLogs(t *testing.T) {
+	factory := NewFactory(WithProber(newFakeProber(nil)))
+	cfg := factory.CreateDefaultConfig()
+	settings := receivertest.NewNopSettings(metadata.Type)
+
+	metricsRecv, err := factory.CreateMetrics(context.Background(), settings, cfg, &consumertest.MetricsSink{})
+	require.NoError(t, err)
+	logsSink := &consumertest.LogsSink{}
+	logsRecv, err := factory.CreateLogs(context.Background(), settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	// Both pipelines share the receiver, so targets are pinged once
+	require.Same(t, metricsRecv, logsRecv)
+	require.Same(t, logsSink, logsRecv.(*icmpReceiver).scraper.logs)
+
+	require.NoError(t, metricsRecv.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, logsRecv.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, metricsRecv.Shutdown(context.Background()))
+	require.NoError(t, logsRecv.Shutdown(context.Background()))
+
+	// A receiver shut down is not reused
+	recv, err := factory.CreateLogs(context.Background(), settings, cfg, logsSink)
+	require.NoError(t, err)
+	require.NotSame(t, logsRecv, recv)
+	require.NoError(t, recv.Shutdown(context.Background()))
+}
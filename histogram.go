@@ -0,0 +1,179 @@
+package icmpreceiver
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	RttModeGauge                = "gauge"
+	RttModeHistogram            = "histogram"
+	RttModeExponentialHistogram = "exponential_histogram"
+)
+
+const (
+	// defaultRttHistogramMaxBuckets is the default maximum number of buckets of
+	// exponential histograms, the same as the OpenTelemetry SDKs.
+	defaultRttHistogramMaxBuckets = 160
+
+	// minExponentialScale and maxExponentialScale bound the resolution of
+	// exponential histograms, as defined by the OpenTelemetry data model.
+	minExponentialScale = -10
+	maxExponentialScale = 20
+)
+
+// defaultRttHistogramBuckets are the explicit bucket boundaries, in
+// milliseconds, used when none are configured.
+var defaultRttHistogramBuckets = []float64{0.5, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+// rttHistogram builds the ping.rtt histogram of a scrape, with one data point
+// per target holding the round-trip times of all packets it received.
+type rttHistogram struct {
+	mode       string
+	buckets    []float64
+	maxBuckets int
+	metric     pmetric.Metric
+}
+
+func newRttHistogram(mode string, buckets []float64, maxBuckets int) *rttHistogram {
+	if len(buckets) == 0 {
+		buckets = defaultRttHistogramBuckets
+	}
+	if maxBuckets <= 0 {
+		maxBuckets = defaultRttHistogramMaxBuckets
+	}
+
+	h := &rttHistogram{mode: mode, buckets: buckets, maxBuckets: maxBuckets}
+	h.reset()
+	return h
+}
+
+func (h *rttHistogram) reset() {
+	h.metric = pmetric.NewMetric()
+	h.metric.SetName("ping.rtt")
+	h.metric.SetDescription("Distribution of the round-trip times of the echo replies received during a scrape.")
+	h.metric.SetUnit("ms")
+	if h.mode == RttModeExponentialHistogram {
+		h.metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	} else {
+		h.metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	}
+}
+
+// record adds a data point for the given round-trip times, in milliseconds,
+// and returns its attributes for the caller to fill.
+func (h *rttHistogram) record(start, ts pcommon.Timestamp, rtts []float64) pcommon.Map {
+	if h.mode == RttModeExponentialHistogram {
+		dp := h.metric.ExponentialHistogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(ts)
+		fillExponentialHistogram(dp, rtts, h.maxBuckets)
+		return dp.Attributes()
+	}
+
+	dp := h.metric.Histogram().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	fillExplicitHistogram(dp, rtts, h.buckets)
+	return dp.Attributes()
+}
+
+// emit moves the data points recorded so far to the first scope of metrics,
+// creating it when the other metrics were all empty.
+func (h *rttHistogram) emit(metrics pmetric.Metrics, scopeName, version string) {
+	if h.dataPointCount() == 0 {
+		return
+	}
+
+	if metrics.ResourceMetrics().Len() == 0 {
+		scope := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Scope()
+		scope.SetName(scopeName)
+		scope.SetVersion(version)
+	}
+	h.metric.MoveTo(metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().AppendEmpty())
+	h.reset()
+}
+
+func (h *rttHistogram) dataPointCount() int {
+	if h.mode == RttModeExponentialHistogram {
+		return h.metric.ExponentialHistogram().DataPoints().Len()
+	}
+	return h.metric.Histogram().DataPoints().Len()
+}
+
+// fillExplicitHistogram counts values into buckets whose upper boundaries are
+// inclusive, plus an overflow bucket for values above the last boundary.
+func fillExplicitHistogram(dp pmetric.HistogramDataPoint, values []float64, buckets []float64) {
+	dp.ExplicitBounds().FromRaw(buckets)
+	counts := make([]uint64, len(buckets)+1)
+	for _, v := range values {
+		counts[sort.SearchFloat64s(buckets, v)]++
+	}
+	dp.BucketCounts().FromRaw(counts)
+
+	dp.SetCount(uint64(len(values)))
+	if len(values) == 0 {
+		return
+	}
+	dp.SetSum(sum(values))
+	dp.SetMin(slices.Min(values))
+	dp.SetMax(slices.Max(values))
+}
+
+// fillExponentialHistogram counts values into buckets of the highest scale
+// at which all of them fit within maxBuckets.
+func fillExponentialHistogram(dp pmetric.ExponentialHistogramDataPoint, values []float64, maxBuckets int) {
+	dp.SetCount(uint64(len(values)))
+
+	var positive []float64
+	for _, v := range values {
+		if v > 0 {
+			positive = append(positive, v)
+		} else {
+			dp.SetZeroCount(dp.ZeroCount() + 1)
+		}
+	}
+	if len(values) > 0 {
+		dp.SetSum(sum(values))
+		dp.SetMin(slices.Min(values))
+		dp.SetMax(slices.Max(values))
+	}
+	if len(positive) == 0 {
+		dp.SetScale(maxExponentialScale)
+		return
+	}
+
+	minValue, maxValue := slices.Min(positive), slices.Max(positive)
+	scale := int32(maxExponentialScale)
+	for scale > minExponentialScale &&
+		exponentialIndex(maxValue, scale)-exponentialIndex(minValue, scale)+1 > int64(maxBuckets) {
+		scale--
+	}
+	dp.SetScale(scale)
+
+	offset := exponentialIndex(minValue, scale)
+	counts := make([]uint64, exponentialIndex(maxValue, scale)-offset+1)
+	for _, v := range positive {
+		counts[exponentialIndex(v, scale)-offset]++
+	}
+	dp.Positive().SetOffset(int32(offset))
+	dp.Positive().BucketCounts().FromRaw(counts)
+}
+
+// exponentialIndex returns the index of the bucket holding v at the given
+// scale. Buckets are (base^index, base^(index+1)] with base 2^(2^-scale).
+func exponentialIndex(v float64, scale int32) int64 {
+	return int64(math.Ceil(math.Ldexp(math.Log2(v), int(scale)))) - 1
+}
+
+func sum(values []float64) float64 {
+	var s float64
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
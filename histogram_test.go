@@ -0,0 +1,88 @@
+package icmpreceiver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestFillExplicitHistogram(t *testing.T) {
+	dp := pmetric.NewHistogramDataPoint()
+	fillExplicitHistogram(dp, []float64{0.3, 1, 1.5, 12, 9000}, []float64{1, 10, 100})
+
+	assert.Equal(t, []float64{1, 10, 100}, dp.ExplicitBounds().AsRaw())
+	// Upper boundaries are inclusive: 1 falls into the first bucket
+	assert.Equal(t, []uint64{2, 1, 1, 1}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.InDelta(t, 9014.8, dp.Sum(), 1e-9)
+	assert.InDelta(t, 0.3, dp.Min(), 1e-9)
+	assert.InDelta(t, 9000., dp.Max(), 1e-9)
+}
+
+func TestFillExplicitHistogramWithoutValues(t *testing.T) {
+	dp := pmetric.NewHistogramDataPoint()
+	fillExplicitHistogram(dp, nil, []float64{1, 10})
+
+	assert.Equal(t, []uint64{0, 0, 0}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(0), dp.Count())
+	assert.False(t, dp.HasMin())
+	assert.False(t, dp.HasMax())
+}
+
+func TestFillExponentialHistogram(t *testing.T) {
+	values := []float64{0, 1, 2, 3, 4, 1000}
+
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	fillExponentialHistogram(dp, values, 20)
+
+	assert.Equal(t, uint64(6), dp.Count())
+	assert.Equal(t, uint64(1), dp.ZeroCount())
+	assert.InDelta(t, 1010., dp.Sum(), 1e-9)
+	assert.LessOrEqual(t, dp.Positive().BucketCounts().Len(), 20)
+
+	// The highest scale at which 1 and 1000 fit within 20 buckets
+	assert.Equal(t, int32(0), dp.Scale())
+	assert.Equal(t, int32(-1), dp.Positive().Offset())
+
+	// Every positive value is within the bounds of its bucket
+	base := math.Exp2(math.Exp2(-float64(dp.Scale())))
+	counts := dp.Positive().BucketCounts().AsRaw()
+	var total uint64
+	for i, count := range counts {
+		total += count
+		if count == 0 {
+			continue
+		}
+		index := float64(int(dp.Positive().Offset()) + i)
+		lower, upper := math.Pow(base, index), math.Pow(base, index+1)
+		found := false
+		for _, v := range values {
+			if v > lower && v <= upper {
+				found = true
+			}
+		}
+		assert.True(t, found, "bucket %d (%v, %v] has no value", i, lower, upper)
+	}
+	assert.Equal(t, uint64(5), total)
+}
+
+func TestFillExponentialHistogramUsesHighestScale(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	fillExponentialHistogram(dp, []float64{10, 10}, 160)
+
+	assert.Equal(t, int32(maxExponentialScale), dp.Scale())
+	assert.Equal(t, []uint64{2}, dp.Positive().BucketCounts().AsRaw())
+}
+
+func TestExponentialIndex(t *testing.T) {
+	// Bucket boundaries are inclusive on the upper side
+	assert.Equal(t, int64(-1), exponentialIndex(1, 0))
+	assert.Equal(t, int64(0), exponentialIndex(2, 0))
+	assert.Equal(t, int64(1), exponentialIndex(3, 0))
+	assert.Equal(t, int64(1), exponentialIndex(4, 0))
+	assert.Equal(t, int64(3), exponentialIndex(3, 1))
+	assert.Equal(t, int64(0), exponentialIndex(4, -1))
+	assert.Equal(t, int64(1), exponentialIndex(5, -1))
+}
@@ -0,0 +1,172 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/filter"
+)
+
+// MetricConfig provides common config for a particular metric.
+type MetricConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	enabledSetByUser bool
+}
+
+func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
+	if parser == nil {
+		return nil
+	}
+	err := parser.Unmarshal(ms)
+	if err != nil {
+		return err
+	}
+	ms.enabledSetByUser = parser.IsSet("enabled")
+	return nil
+}
+
+// MetricsConfig provides config for icmpcheck metrics.
+type MetricsConfig struct {
+	PingDNSDuration      MetricConfig `mapstructure:"ping.dns.duration"`
+	PingDNSErrors        MetricConfig `mapstructure:"ping.dns.errors"`
+	PingIpdvAvg          MetricConfig `mapstructure:"ping.ipdv.avg"`
+	PingIpdvMax          MetricConfig `mapstructure:"ping.ipdv.max"`
+	PingIpdvMin          MetricConfig `mapstructure:"ping.ipdv.min"`
+	PingJitter           MetricConfig `mapstructure:"ping.jitter"`
+	PingLossRatio        MetricConfig `mapstructure:"ping.loss.ratio"`
+	PingPacketsDuplicate MetricConfig `mapstructure:"ping.packets.duplicate"`
+	PingPacketsReceived  MetricConfig `mapstructure:"ping.packets.received"`
+	PingPacketsSent      MetricConfig `mapstructure:"ping.packets.sent"`
+	PingReachable        MetricConfig `mapstructure:"ping.reachable"`
+	PingRtt              MetricConfig `mapstructure:"ping.rtt"`
+	PingRttAvg           MetricConfig `mapstructure:"ping.rtt.avg"`
+	PingRttMax           MetricConfig `mapstructure:"ping.rtt.max"`
+	PingRttMin           MetricConfig `mapstructure:"ping.rtt.min"`
+	PingRttP50           MetricConfig `mapstructure:"ping.rtt.p50"`
+	PingRttP90           MetricConfig `mapstructure:"ping.rtt.p90"`
+	PingRttP99           MetricConfig `mapstructure:"ping.rtt.p99"`
+	PingRttStddev        MetricConfig `mapstructure:"ping.rtt.stddev"`
+	PingRttTrimmedMean   MetricConfig `mapstructure:"ping.rtt.trimmed_mean"`
+}
+
+func DefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		PingDNSDuration: MetricConfig{
+			Enabled: true,
+		},
+		PingDNSErrors: MetricConfig{
+			Enabled: true,
+		},
+		PingIpdvAvg: MetricConfig{
+			Enabled: false,
+		},
+		PingIpdvMax: MetricConfig{
+			Enabled: false,
+		},
+		PingIpdvMin: MetricConfig{
+			Enabled: false,
+		},
+		PingJitter: MetricConfig{
+			Enabled: true,
+		},
+		PingLossRatio: MetricConfig{
+			Enabled: true,
+		},
+		PingPacketsDuplicate: MetricConfig{
+			Enabled: true,
+		},
+		PingPacketsReceived: MetricConfig{
+			Enabled: true,
+		},
+		PingPacketsSent: MetricConfig{
+			Enabled: true,
+		},
+		PingReachable: MetricConfig{
+			Enabled: true,
+		},
+		PingRtt: MetricConfig{
+			Enabled: true,
+		},
+		PingRttAvg: MetricConfig{
+			Enabled: true,
+		},
+		PingRttMax: MetricConfig{
+			Enabled: true,
+		},
+		PingRttMin: MetricConfig{
+			Enabled: true,
+		},
+		PingRttP50: MetricConfig{
+			Enabled: true,
+		},
+		PingRttP90: MetricConfig{
+			Enabled: true,
+		},
+		PingRttP99: MetricConfig{
+			Enabled: true,
+		},
+		PingRttStddev: MetricConfig{
+			Enabled: true,
+		},
+		PingRttTrimmedMean: MetricConfig{
+			Enabled: false,
+		},
+	}
+}
+
+// ResourceAttributeConfig provides common config for a particular resource attribute.
+type ResourceAttributeConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Experimental: MetricsInclude defines a list of filters for attribute values.
+	// If the list is not empty, only metrics with matching resource attribute values will be emitted.
+	MetricsInclude []filter.Config `mapstructure:"metrics_include"`
+	// Experimental: MetricsExclude defines a list of filters for attribute values.
+	// If the list is not empty, metrics with matching resource attribute values will not be emitted.
+	// MetricsInclude has higher priority than MetricsExclude.
+	MetricsExclude []filter.Config `mapstructure:"metrics_exclude"`
+
+	enabledSetByUser bool
+}
+
+func (rac *ResourceAttributeConfig) Unmarshal(parser *confmap.Conf) error {
+	if parser == nil {
+		return nil
+	}
+	err := parser.Unmarshal(rac)
+	if err != nil {
+		return err
+	}
+	rac.enabledSetByUser = parser.IsSet("enabled")
+	return nil
+}
+
+// ResourceAttributesConfig provides config for icmpcheck resource attributes.
+type ResourceAttributesConfig struct {
+	HostName              ResourceAttributeConfig `mapstructure:"host.name"`
+	IcmpcheckReceiverName ResourceAttributeConfig `mapstructure:"icmpcheck.receiver.name"`
+}
+
+func DefaultResourceAttributesConfig() ResourceAttributesConfig {
+	return ResourceAttributesConfig{
+		HostName: ResourceAttributeConfig{
+			Enabled: false,
+		},
+		IcmpcheckReceiverName: ResourceAttributeConfig{
+			Enabled: false,
+		},
+	}
+}
+
+// MetricsBuilderConfig is a configuration for icmpcheck metrics builder.
+type MetricsBuilderConfig struct {
+	Metrics            MetricsConfig            `mapstructure:"metrics"`
+	ResourceAttributes ResourceAttributesConfig `mapstructure:"resource_attributes"`
+}
+
+func DefaultMetricsBuilderConfig() MetricsBuilderConfig {
+	return MetricsBuilderConfig{
+		Metrics:            DefaultMetricsConfig(),
+		ResourceAttributes: DefaultResourceAttributesConfig(),
+	}
+}
@@ -0,0 +1,151 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+func TestMetricsBuilderConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		want MetricsBuilderConfig
+	}{
+		{
+			name: "default",
+			want: DefaultMetricsBuilderConfig(),
+		},
+		{
+			name: "all_set",
+			want: MetricsBuilderConfig{
+				Metrics: MetricsConfig{
+					PingDNSDuration:      MetricConfig{Enabled: true},
+					PingDNSErrors:        MetricConfig{Enabled: true},
+					PingIpdvAvg:          MetricConfig{Enabled: true},
+					PingIpdvMax:          MetricConfig{Enabled: true},
+					PingIpdvMin:          MetricConfig{Enabled: true},
+					PingJitter:           MetricConfig{Enabled: true},
+					PingLossRatio:        MetricConfig{Enabled: true},
+					PingPacketsDuplicate: MetricConfig{Enabled: true},
+					PingPacketsReceived:  MetricConfig{Enabled: true},
+					PingPacketsSent:      MetricConfig{Enabled: true},
+					PingReachable:        MetricConfig{Enabled: true},
+					PingRtt:              MetricConfig{Enabled: true},
+					PingRttAvg:           MetricConfig{Enabled: true},
+					PingRttMax:           MetricConfig{Enabled: true},
+					PingRttMin:           MetricConfig{Enabled: true},
+					PingRttP50:           MetricConfig{Enabled: true},
+					PingRttP90:           MetricConfig{Enabled: true},
+					PingRttP99:           MetricConfig{Enabled: true},
+					PingRttStddev:        MetricConfig{Enabled: true},
+					PingRttTrimmedMean:   MetricConfig{Enabled: true},
+				},
+				ResourceAttributes: ResourceAttributesConfig{
+					HostName:              ResourceAttributeConfig{Enabled: true},
+					IcmpcheckReceiverName: ResourceAttributeConfig{Enabled: true},
+				},
+			},
+		},
+		{
+			name: "none_set",
+			want: MetricsBuilderConfig{
+				Metrics: MetricsConfig{
+					PingDNSDuration:      MetricConfig{Enabled: false},
+					PingDNSErrors:        MetricConfig{Enabled: false},
+					PingIpdvAvg:          MetricConfig{Enabled: false},
+					PingIpdvMax:          MetricConfig{Enabled: false},
+					PingIpdvMin:          MetricConfig{Enabled: false},
+					PingJitter:           MetricConfig{Enabled: false},
+					PingLossRatio:        MetricConfig{Enabled: false},
+					PingPacketsDuplicate: MetricConfig{Enabled: false},
+					PingPacketsReceived:  MetricConfig{Enabled: false},
+					PingPacketsSent:      MetricConfig{Enabled: false},
+					PingReachable:        MetricConfig{Enabled: false},
+					PingRtt:              MetricConfig{Enabled: false},
+					PingRttAvg:           MetricConfig{Enabled: false},
+					PingRttMax:           MetricConfig{Enabled: false},
+					PingRttMin:           MetricConfig{Enabled: false},
+					PingRttP50:           MetricConfig{Enabled: false},
+					PingRttP90:           MetricConfig{Enabled: false},
+					PingRttP99:           MetricConfig{Enabled: false},
+					PingRttStddev:        MetricConfig{Enabled: false},
+					PingRttTrimmedMean:   MetricConfig{Enabled: false},
+				},
+				ResourceAttributes: ResourceAttributesConfig{
+					HostName:              ResourceAttributeConfig{Enabled: false},
+					IcmpcheckReceiverName: ResourceAttributeConfig{Enabled: false},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadMetricsBuilderConfig(t, tt.name)
+			diff := cmp.Diff(tt.want, cfg, cmpopts.IgnoreUnexported(MetricConfig{}, ResourceAttributeConfig{}))
+			require.Emptyf(t, diff, "Config mismatch (-expected +actual):\n%s", diff)
+		})
+	}
+}
+
+func loadMetricsBuilderConfig(t *testing.T, name string) MetricsBuilderConfig {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	sub, err := cm.Sub(name)
+	require.NoError(t, err)
+	cfg := DefaultMetricsBuilderConfig()
+	require.NoError(t, sub.Unmarshal(&cfg, confmap.WithIgnoreUnused()))
+	return cfg
+}
+
+func TestResourceAttributesConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		want ResourceAttributesConfig
+	}{
+		{
+			name: "default",
+			want: DefaultResourceAttributesConfig(),
+		},
+		{
+			name: "all_set",
+			want: ResourceAttributesConfig{
+				HostName:              ResourceAttributeConfig{Enabled: true},
+				IcmpcheckReceiverName: ResourceAttributeConfig{Enabled: true},
+			},
+		},
+		{
+			name: "none_set",
+			want: ResourceAttributesConfig{
+				HostName:              ResourceAttributeConfig{Enabled: false},
+				IcmpcheckReceiverName: ResourceAttributeConfig{Enabled: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadResourceAttributesConfig(t, tt.name)
+			diff := cmp.Diff(tt.want, cfg, cmpopts.IgnoreUnexported(ResourceAttributeConfig{}))
+			require.Emptyf(t, diff, "Config mismatch (-expected +actual):\n%s", diff)
+		})
+	}
+}
+
+func loadResourceAttributesConfig(t *testing.T, name string) ResourceAttributesConfig {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	sub, err := cm.Sub(name)
+	require.NoError(t, err)
+	sub, err = sub.Sub("resource_attributes")
+	require.NoError(t, err)
+	cfg := DefaultResourceAttributesConfig()
+	require.NoError(t, sub.Unmarshal(&cfg))
+	return cfg
+}
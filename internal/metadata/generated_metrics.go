@@ -0,0 +1,1685 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/filter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// AttributeDNSLookupType specifies the value dns.lookup.type attribute.
+type AttributeDNSLookupType int
+
+const (
+	_ AttributeDNSLookupType = iota
+	AttributeDNSLookupTypeIp
+	AttributeDNSLookupTypeIP4
+	AttributeDNSLookupTypeIP6
+	AttributeDNSLookupTypeSrv
+)
+
+// String returns the string representation of the AttributeDNSLookupType.
+func (av AttributeDNSLookupType) String() string {
+	switch av {
+	case AttributeDNSLookupTypeIp:
+		return "ip"
+	case AttributeDNSLookupTypeIP4:
+		return "ip4"
+	case AttributeDNSLookupTypeIP6:
+		return "ip6"
+	case AttributeDNSLookupTypeSrv:
+		return "srv"
+	}
+	return ""
+}
+
+// MapAttributeDNSLookupType is a helper map of string to AttributeDNSLookupType attribute value.
+var MapAttributeDNSLookupType = map[string]AttributeDNSLookupType{
+	"ip":  AttributeDNSLookupTypeIp,
+	"ip4": AttributeDNSLookupTypeIP4,
+	"ip6": AttributeDNSLookupTypeIP6,
+	"srv": AttributeDNSLookupTypeSrv,
+}
+
+// AttributeErrorType specifies the value error.type attribute.
+type AttributeErrorType int
+
+const (
+	_ AttributeErrorType = iota
+	AttributeErrorTypeNotFound
+	AttributeErrorTypeTimeout
+	AttributeErrorTypeTemporary
+	AttributeErrorTypeOther
+)
+
+// String returns the string representation of the AttributeErrorType.
+func (av AttributeErrorType) String() string {
+	switch av {
+	case AttributeErrorTypeNotFound:
+		return "not_found"
+	case AttributeErrorTypeTimeout:
+		return "timeout"
+	case AttributeErrorTypeTemporary:
+		return "temporary"
+	case AttributeErrorTypeOther:
+		return "other"
+	}
+	return ""
+}
+
+// MapAttributeErrorType is a helper map of string to AttributeErrorType attribute value.
+var MapAttributeErrorType = map[string]AttributeErrorType{
+	"not_found": AttributeErrorTypeNotFound,
+	"timeout":   AttributeErrorTypeTimeout,
+	"temporary": AttributeErrorTypeTemporary,
+	"other":     AttributeErrorTypeOther,
+}
+
+// AttributeNetSockFamily specifies the value net.sock.family attribute.
+type AttributeNetSockFamily int
+
+const (
+	_ AttributeNetSockFamily = iota
+	AttributeNetSockFamilyInet
+	AttributeNetSockFamilyInet6
+)
+
+// String returns the string representation of the AttributeNetSockFamily.
+func (av AttributeNetSockFamily) String() string {
+	switch av {
+	case AttributeNetSockFamilyInet:
+		return "inet"
+	case AttributeNetSockFamilyInet6:
+		return "inet6"
+	}
+	return ""
+}
+
+// MapAttributeNetSockFamily is a helper map of string to AttributeNetSockFamily attribute value.
+var MapAttributeNetSockFamily = map[string]AttributeNetSockFamily{
+	"inet":  AttributeNetSockFamilyInet,
+	"inet6": AttributeNetSockFamilyInet6,
+}
+
+var MetricsInfo = metricsInfo{
+	PingDNSDuration: metricInfo{
+		Name: "ping.dns.duration",
+	},
+	PingDNSErrors: metricInfo{
+		Name: "ping.dns.errors",
+	},
+	PingIpdvAvg: metricInfo{
+		Name: "ping.ipdv.avg",
+	},
+	PingIpdvMax: metricInfo{
+		Name: "ping.ipdv.max",
+	},
+	PingIpdvMin: metricInfo{
+		Name: "ping.ipdv.min",
+	},
+	PingJitter: metricInfo{
+		Name: "ping.jitter",
+	},
+	PingLossRatio: metricInfo{
+		Name: "ping.loss.ratio",
+	},
+	PingPacketsDuplicate: metricInfo{
+		Name: "ping.packets.duplicate",
+	},
+	PingPacketsReceived: metricInfo{
+		Name: "ping.packets.received",
+	},
+	PingPacketsSent: metricInfo{
+		Name: "ping.packets.sent",
+	},
+	PingReachable: metricInfo{
+		Name: "ping.reachable",
+	},
+	PingRtt: metricInfo{
+		Name: "ping.rtt",
+	},
+	PingRttAvg: metricInfo{
+		Name: "ping.rtt.avg",
+	},
+	PingRttMax: metricInfo{
+		Name: "ping.rtt.max",
+	},
+	PingRttMin: metricInfo{
+		Name: "ping.rtt.min",
+	},
+	PingRttP50: metricInfo{
+		Name: "ping.rtt.p50",
+	},
+	PingRttP90: metricInfo{
+		Name: "ping.rtt.p90",
+	},
+	PingRttP99: metricInfo{
+		Name: "ping.rtt.p99",
+	},
+	PingRttStddev: metricInfo{
+		Name: "ping.rtt.stddev",
+	},
+	PingRttTrimmedMean: metricInfo{
+		Name: "ping.rtt.trimmed_mean",
+	},
+}
+
+type metricsInfo struct {
+	PingDNSDuration      metricInfo
+	PingDNSErrors        metricInfo
+	PingIpdvAvg          metricInfo
+	PingIpdvMax          metricInfo
+	PingIpdvMin          metricInfo
+	PingJitter           metricInfo
+	PingLossRatio        metricInfo
+	PingPacketsDuplicate metricInfo
+	PingPacketsReceived  metricInfo
+	PingPacketsSent      metricInfo
+	PingReachable        metricInfo
+	PingRtt              metricInfo
+	PingRttAvg           metricInfo
+	PingRttMax           metricInfo
+	PingRttMin           metricInfo
+	PingRttP50           metricInfo
+	PingRttP90           metricInfo
+	PingRttP99           metricInfo
+	PingRttStddev        metricInfo
+	PingRttTrimmedMean   metricInfo
+}
+
+type metricInfo struct {
+	Name string
+}
+
+type MetricAttributeOption interface {
+	apply(pmetric.NumberDataPoint)
+}
+
+type metricAttributeOptionFunc func(pmetric.NumberDataPoint)
+
+func (maof metricAttributeOptionFunc) apply(dp pmetric.NumberDataPoint) {
+	maof(dp)
+}
+
+func WithNetHostInterfaceMetricAttribute(netHostInterfaceAttributeValue string) MetricAttributeOption {
+	return metricAttributeOptionFunc(func(dp pmetric.NumberDataPoint) {
+		dp.Attributes().PutStr("net.host.interface", netHostInterfaceAttributeValue)
+	})
+}
+
+func WithNetHostIPMetricAttribute(netHostIPAttributeValue string) MetricAttributeOption {
+	return metricAttributeOptionFunc(func(dp pmetric.NumberDataPoint) {
+		dp.Attributes().PutStr("net.host.ip", netHostIPAttributeValue)
+	})
+}
+
+func WithNetPeerPrefixMetricAttribute(netPeerPrefixAttributeValue string) MetricAttributeOption {
+	return metricAttributeOptionFunc(func(dp pmetric.NumberDataPoint) {
+		dp.Attributes().PutStr("net.peer.prefix", netPeerPrefixAttributeValue)
+	})
+}
+
+type metricPingDNSDuration struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.dns.duration metric with initial data.
+func (m *metricPingDNSDuration) init() {
+	m.data.SetName("ping.dns.duration")
+	m.data.SetDescription("Time spent resolving the host name of a target during a scrape. Answers served from the cache take close to 0.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingDNSDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerNameAttributeValue string, dnsLookupTypeAttributeValue string, tagAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("dns.lookup.type", dnsLookupTypeAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingDNSDuration) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingDNSDuration) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingDNSDuration(cfg MetricConfig) metricPingDNSDuration {
+	m := metricPingDNSDuration{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingDNSErrors struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.dns.errors metric with initial data.
+func (m *metricPingDNSErrors) init() {
+	m.data.SetName("ping.dns.errors")
+	m.data.SetDescription("Number of failed lookups of the host name of a target since the receiver started.")
+	m.data.SetUnit("{error}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingDNSErrors) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, netPeerNameAttributeValue string, dnsLookupTypeAttributeValue string, errorTypeAttributeValue string, tagAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("dns.lookup.type", dnsLookupTypeAttributeValue)
+	dp.Attributes().PutStr("error.type", errorTypeAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingDNSErrors) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingDNSErrors) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingDNSErrors(cfg MetricConfig) metricPingDNSErrors {
+	m := metricPingDNSErrors{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingIpdvAvg struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.ipdv.avg metric with initial data.
+func (m *metricPingIpdvAvg) init() {
+	m.data.SetName("ping.ipdv.avg")
+	m.data.SetDescription("Average absolute IP packet delay variation (RFC 3393) between echo requests of consecutive sequence numbers during a scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingIpdvAvg) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingIpdvAvg) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingIpdvAvg) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingIpdvAvg(cfg MetricConfig) metricPingIpdvAvg {
+	m := metricPingIpdvAvg{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingIpdvMax struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.ipdv.max metric with initial data.
+func (m *metricPingIpdvMax) init() {
+	m.data.SetName("ping.ipdv.max")
+	m.data.SetDescription("Maximum IP packet delay variation (RFC 3393) between echo requests of consecutive sequence numbers during a scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingIpdvMax) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingIpdvMax) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingIpdvMax) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingIpdvMax(cfg MetricConfig) metricPingIpdvMax {
+	m := metricPingIpdvMax{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingIpdvMin struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.ipdv.min metric with initial data.
+func (m *metricPingIpdvMin) init() {
+	m.data.SetName("ping.ipdv.min")
+	m.data.SetDescription("Minimum IP packet delay variation (RFC 3393) between echo requests of consecutive sequence numbers during a scrape. Negative when a reply came back faster than the previous one.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingIpdvMin) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingIpdvMin) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingIpdvMin) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingIpdvMin(cfg MetricConfig) metricPingIpdvMin {
+	m := metricPingIpdvMin{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingJitter struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.jitter metric with initial data.
+func (m *metricPingJitter) init() {
+	m.data.SetName("ping.jitter")
+	m.data.SetDescription("Interarrival jitter of the echo replies received during a scrape, as defined by RFC 3550, computed from the round-trip times of consecutive replies. Requires at least 2 replies.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingJitter) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingJitter) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingJitter) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingJitter(cfg MetricConfig) metricPingJitter {
+	m := metricPingJitter{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingLossRatio struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.loss.ratio metric with initial data.
+func (m *metricPingLossRatio) init() {
+	m.data.SetName("ping.loss.ratio")
+	m.data.SetDescription("Ratio of echo requests sent during a scrape that got no reply, between 0 and 1.")
+	m.data.SetUnit("1")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingLossRatio) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingLossRatio) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingLossRatio) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingLossRatio(cfg MetricConfig) metricPingLossRatio {
+	m := metricPingLossRatio{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingPacketsDuplicate struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.packets.duplicate metric with initial data.
+func (m *metricPingPacketsDuplicate) init() {
+	m.data.SetName("ping.packets.duplicate")
+	m.data.SetDescription("Number of duplicate echo replies received from the target since it was first pinged.")
+	m.data.SetUnit("{packet}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingPacketsDuplicate) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingPacketsDuplicate) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingPacketsDuplicate) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingPacketsDuplicate(cfg MetricConfig) metricPingPacketsDuplicate {
+	m := metricPingPacketsDuplicate{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingPacketsReceived struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.packets.received metric with initial data.
+func (m *metricPingPacketsReceived) init() {
+	m.data.SetName("ping.packets.received")
+	m.data.SetDescription("Number of echo replies received from the target since it was first pinged, duplicates excluded.")
+	m.data.SetUnit("{packet}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingPacketsReceived) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingPacketsReceived) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingPacketsReceived) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingPacketsReceived(cfg MetricConfig) metricPingPacketsReceived {
+	m := metricPingPacketsReceived{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingPacketsSent struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.packets.sent metric with initial data.
+func (m *metricPingPacketsSent) init() {
+	m.data.SetName("ping.packets.sent")
+	m.data.SetDescription("Number of echo requests sent to the target since it was first pinged.")
+	m.data.SetUnit("{packet}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingPacketsSent) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingPacketsSent) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingPacketsSent) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingPacketsSent(cfg MetricConfig) metricPingPacketsSent {
+	m := metricPingPacketsSent{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingReachable struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.reachable metric with initial data.
+func (m *metricPingReachable) init() {
+	m.data.SetName("ping.reachable")
+	m.data.SetDescription("Whether the target answered at least one echo request during a scrape (1) or not (0). Targets that could not be pinged are reported unreachable, with an empty net.peer.ip when their host name cannot be resolved.")
+	m.data.SetUnit("1")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingReachable) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingReachable) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingReachable) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingReachable(cfg MetricConfig) metricPingReachable {
+	m := metricPingReachable{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRtt struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt metric with initial data.
+func (m *metricPingRtt) init() {
+	m.data.SetName("ping.rtt")
+	m.data.SetDescription("Round-trip time of a single echo reply. One data point is recorded per received packet.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRtt) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRtt) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRtt) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRtt(cfg MetricConfig) metricPingRtt {
+	m := metricPingRtt{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttAvg struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.avg metric with initial data.
+func (m *metricPingRttAvg) init() {
+	m.data.SetName("ping.rtt.avg")
+	m.data.SetDescription("Average round-trip time of the echo replies received during a scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttAvg) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttAvg) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttAvg) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttAvg(cfg MetricConfig) metricPingRttAvg {
+	m := metricPingRttAvg{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttMax struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.max metric with initial data.
+func (m *metricPingRttMax) init() {
+	m.data.SetName("ping.rtt.max")
+	m.data.SetDescription("Maximum round-trip time of the echo replies received during a scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttMax) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttMax) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttMax) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttMax(cfg MetricConfig) metricPingRttMax {
+	m := metricPingRttMax{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttMin struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.min metric with initial data.
+func (m *metricPingRttMin) init() {
+	m.data.SetName("ping.rtt.min")
+	m.data.SetDescription("Minimum round-trip time of the echo replies received during a scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttMin) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttMin) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttMin) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttMin(cfg MetricConfig) metricPingRttMin {
+	m := metricPingRttMin{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttP50 struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.p50 metric with initial data.
+func (m *metricPingRttP50) init() {
+	m.data.SetName("ping.rtt.p50")
+	m.data.SetDescription("Median round-trip time of the echo replies received during a scrape, interpolated between the closest replies.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttP50) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttP50) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttP50) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttP50(cfg MetricConfig) metricPingRttP50 {
+	m := metricPingRttP50{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttP90 struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.p90 metric with initial data.
+func (m *metricPingRttP90) init() {
+	m.data.SetName("ping.rtt.p90")
+	m.data.SetDescription("90th percentile of the round-trip time of the echo replies received during a scrape, interpolated between the closest replies.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttP90) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttP90) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttP90) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttP90(cfg MetricConfig) metricPingRttP90 {
+	m := metricPingRttP90{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttP99 struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.p99 metric with initial data.
+func (m *metricPingRttP99) init() {
+	m.data.SetName("ping.rtt.p99")
+	m.data.SetDescription("99th percentile of the round-trip time of the echo replies received during a scrape, interpolated between the closest replies.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttP99) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttP99) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttP99) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttP99(cfg MetricConfig) metricPingRttP99 {
+	m := metricPingRttP99{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttStddev struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.stddev metric with initial data.
+func (m *metricPingRttStddev) init() {
+	m.data.SetName("ping.rtt.stddev")
+	m.data.SetDescription("Standard deviation of the round-trip time of the echo replies received during a scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttStddev) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttStddev) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttStddev) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttStddev(cfg MetricConfig) metricPingRttStddev {
+	m := metricPingRttStddev{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricPingRttTrimmedMean struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills ping.rtt.trimmed_mean metric with initial data.
+func (m *metricPingRttTrimmedMean) init() {
+	m.data.SetName("ping.rtt.trimmed_mean")
+	m.data.SetDescription("Average round-trip time of the echo replies received during a scrape, once the `rtt_trim_ratio` fastest and slowest replies are dropped.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricPingRttTrimmedMean) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue string, tagAttributeValue string, options ...MetricAttributeOption) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("net.peer.ip", netPeerIPAttributeValue)
+	dp.Attributes().PutStr("net.peer.name", netPeerNameAttributeValue)
+	dp.Attributes().PutStr("net.sock.family", netSockFamilyAttributeValue)
+	dp.Attributes().PutStr("tag", tagAttributeValue)
+	for _, op := range options {
+		op.apply(dp)
+	}
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricPingRttTrimmedMean) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricPingRttTrimmedMean) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricPingRttTrimmedMean(cfg MetricConfig) metricPingRttTrimmedMean {
+	m := metricPingRttTrimmedMean{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+// MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
+// required to produce metric representation defined in metadata and user config.
+type MetricsBuilder struct {
+	config                         MetricsBuilderConfig // config of the metrics builder.
+	startTime                      pcommon.Timestamp    // start time that will be applied to all recorded data points.
+	metricsCapacity                int                  // maximum observed number of metrics per resource.
+	metricsBuffer                  pmetric.Metrics      // accumulates metrics data before emitting.
+	buildInfo                      component.BuildInfo  // contains version information.
+	resourceAttributeIncludeFilter map[string]filter.Filter
+	resourceAttributeExcludeFilter map[string]filter.Filter
+	metricPingDNSDuration          metricPingDNSDuration
+	metricPingDNSErrors            metricPingDNSErrors
+	metricPingIpdvAvg              metricPingIpdvAvg
+	metricPingIpdvMax              metricPingIpdvMax
+	metricPingIpdvMin              metricPingIpdvMin
+	metricPingJitter               metricPingJitter
+	metricPingLossRatio            metricPingLossRatio
+	metricPingPacketsDuplicate     metricPingPacketsDuplicate
+	metricPingPacketsReceived      metricPingPacketsReceived
+	metricPingPacketsSent          metricPingPacketsSent
+	metricPingReachable            metricPingReachable
+	metricPingRtt                  metricPingRtt
+	metricPingRttAvg               metricPingRttAvg
+	metricPingRttMax               metricPingRttMax
+	metricPingRttMin               metricPingRttMin
+	metricPingRttP50               metricPingRttP50
+	metricPingRttP90               metricPingRttP90
+	metricPingRttP99               metricPingRttP99
+	metricPingRttStddev            metricPingRttStddev
+	metricPingRttTrimmedMean       metricPingRttTrimmedMean
+}
+
+// MetricBuilderOption applies changes to default metrics builder.
+type MetricBuilderOption interface {
+	apply(*MetricsBuilder)
+}
+
+type metricBuilderOptionFunc func(mb *MetricsBuilder)
+
+func (mbof metricBuilderOptionFunc) apply(mb *MetricsBuilder) {
+	mbof(mb)
+}
+
+// WithStartTime sets startTime on the metrics builder.
+func WithStartTime(startTime pcommon.Timestamp) MetricBuilderOption {
+	return metricBuilderOptionFunc(func(mb *MetricsBuilder) {
+		mb.startTime = startTime
+	})
+}
+func NewMetricsBuilder(mbc MetricsBuilderConfig, settings receiver.Settings, options ...MetricBuilderOption) *MetricsBuilder {
+	mb := &MetricsBuilder{
+		config:                         mbc,
+		startTime:                      pcommon.NewTimestampFromTime(time.Now()),
+		metricsBuffer:                  pmetric.NewMetrics(),
+		buildInfo:                      settings.BuildInfo,
+		metricPingDNSDuration:          newMetricPingDNSDuration(mbc.Metrics.PingDNSDuration),
+		metricPingDNSErrors:            newMetricPingDNSErrors(mbc.Metrics.PingDNSErrors),
+		metricPingIpdvAvg:              newMetricPingIpdvAvg(mbc.Metrics.PingIpdvAvg),
+		metricPingIpdvMax:              newMetricPingIpdvMax(mbc.Metrics.PingIpdvMax),
+		metricPingIpdvMin:              newMetricPingIpdvMin(mbc.Metrics.PingIpdvMin),
+		metricPingJitter:               newMetricPingJitter(mbc.Metrics.PingJitter),
+		metricPingLossRatio:            newMetricPingLossRatio(mbc.Metrics.PingLossRatio),
+		metricPingPacketsDuplicate:     newMetricPingPacketsDuplicate(mbc.Metrics.PingPacketsDuplicate),
+		metricPingPacketsReceived:      newMetricPingPacketsReceived(mbc.Metrics.PingPacketsReceived),
+		metricPingPacketsSent:          newMetricPingPacketsSent(mbc.Metrics.PingPacketsSent),
+		metricPingReachable:            newMetricPingReachable(mbc.Metrics.PingReachable),
+		metricPingRtt:                  newMetricPingRtt(mbc.Metrics.PingRtt),
+		metricPingRttAvg:               newMetricPingRttAvg(mbc.Metrics.PingRttAvg),
+		metricPingRttMax:               newMetricPingRttMax(mbc.Metrics.PingRttMax),
+		metricPingRttMin:               newMetricPingRttMin(mbc.Metrics.PingRttMin),
+		metricPingRttP50:               newMetricPingRttP50(mbc.Metrics.PingRttP50),
+		metricPingRttP90:               newMetricPingRttP90(mbc.Metrics.PingRttP90),
+		metricPingRttP99:               newMetricPingRttP99(mbc.Metrics.PingRttP99),
+		metricPingRttStddev:            newMetricPingRttStddev(mbc.Metrics.PingRttStddev),
+		metricPingRttTrimmedMean:       newMetricPingRttTrimmedMean(mbc.Metrics.PingRttTrimmedMean),
+		resourceAttributeIncludeFilter: make(map[string]filter.Filter),
+		resourceAttributeExcludeFilter: make(map[string]filter.Filter),
+	}
+	if mbc.ResourceAttributes.HostName.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["host.name"] = filter.CreateFilter(mbc.ResourceAttributes.HostName.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.HostName.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["host.name"] = filter.CreateFilter(mbc.ResourceAttributes.HostName.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IcmpcheckReceiverName.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["icmpcheck.receiver.name"] = filter.CreateFilter(mbc.ResourceAttributes.IcmpcheckReceiverName.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IcmpcheckReceiverName.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["icmpcheck.receiver.name"] = filter.CreateFilter(mbc.ResourceAttributes.IcmpcheckReceiverName.MetricsExclude)
+	}
+
+	for _, op := range options {
+		op.apply(mb)
+	}
+	return mb
+}
+
+// NewResourceBuilder returns a new resource builder that should be used to build a resource associated with for the emitted metrics.
+func (mb *MetricsBuilder) NewResourceBuilder() *ResourceBuilder {
+	return NewResourceBuilder(mb.config.ResourceAttributes)
+}
+
+// updateCapacity updates max length of metrics and resource attributes that will be used for the slice capacity.
+func (mb *MetricsBuilder) updateCapacity(rm pmetric.ResourceMetrics) {
+	if mb.metricsCapacity < rm.ScopeMetrics().At(0).Metrics().Len() {
+		mb.metricsCapacity = rm.ScopeMetrics().At(0).Metrics().Len()
+	}
+}
+
+// ResourceMetricsOption applies changes to provided resource metrics.
+type ResourceMetricsOption interface {
+	apply(pmetric.ResourceMetrics)
+}
+
+type resourceMetricsOptionFunc func(pmetric.ResourceMetrics)
+
+func (rmof resourceMetricsOptionFunc) apply(rm pmetric.ResourceMetrics) {
+	rmof(rm)
+}
+
+// WithResource sets the provided resource on the emitted ResourceMetrics.
+// It's recommended to use ResourceBuilder to create the resource.
+func WithResource(res pcommon.Resource) ResourceMetricsOption {
+	return resourceMetricsOptionFunc(func(rm pmetric.ResourceMetrics) {
+		res.CopyTo(rm.Resource())
+	})
+}
+
+// WithStartTimeOverride overrides start time for all the resource metrics data points.
+// This option should be only used if different start time has to be set on metrics coming from different resources.
+func WithStartTimeOverride(start pcommon.Timestamp) ResourceMetricsOption {
+	return resourceMetricsOptionFunc(func(rm pmetric.ResourceMetrics) {
+		var dps pmetric.NumberDataPointSlice
+		metrics := rm.ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			switch metrics.At(i).Type() {
+			case pmetric.MetricTypeGauge:
+				dps = metrics.At(i).Gauge().DataPoints()
+			case pmetric.MetricTypeSum:
+				dps = metrics.At(i).Sum().DataPoints()
+			}
+			for j := 0; j < dps.Len(); j++ {
+				dps.At(j).SetStartTimestamp(start)
+			}
+		}
+	})
+}
+
+// EmitForResource saves all the generated metrics under a new resource and updates the internal state to be ready for
+// recording another set of data points as part of another resource. This function can be helpful when one scraper
+// needs to emit metrics from several resources. Otherwise calling this function is not required,
+// just `Emit` function can be called instead.
+// Resource attributes should be provided as ResourceMetricsOption arguments.
+func (mb *MetricsBuilder) EmitForResource(options ...ResourceMetricsOption) {
+	rm := pmetric.NewResourceMetrics()
+	ils := rm.ScopeMetrics().AppendEmpty()
+	ils.Scope().SetName(ScopeName)
+	ils.Scope().SetVersion(mb.buildInfo.Version)
+	ils.Metrics().EnsureCapacity(mb.metricsCapacity)
+	mb.metricPingDNSDuration.emit(ils.Metrics())
+	mb.metricPingDNSErrors.emit(ils.Metrics())
+	mb.metricPingIpdvAvg.emit(ils.Metrics())
+	mb.metricPingIpdvMax.emit(ils.Metrics())
+	mb.metricPingIpdvMin.emit(ils.Metrics())
+	mb.metricPingJitter.emit(ils.Metrics())
+	mb.metricPingLossRatio.emit(ils.Metrics())
+	mb.metricPingPacketsDuplicate.emit(ils.Metrics())
+	mb.metricPingPacketsReceived.emit(ils.Metrics())
+	mb.metricPingPacketsSent.emit(ils.Metrics())
+	mb.metricPingReachable.emit(ils.Metrics())
+	mb.metricPingRtt.emit(ils.Metrics())
+	mb.metricPingRttAvg.emit(ils.Metrics())
+	mb.metricPingRttMax.emit(ils.Metrics())
+	mb.metricPingRttMin.emit(ils.Metrics())
+	mb.metricPingRttP50.emit(ils.Metrics())
+	mb.metricPingRttP90.emit(ils.Metrics())
+	mb.metricPingRttP99.emit(ils.Metrics())
+	mb.metricPingRttStddev.emit(ils.Metrics())
+	mb.metricPingRttTrimmedMean.emit(ils.Metrics())
+
+	for _, op := range options {
+		op.apply(rm)
+	}
+	for attr, filter := range mb.resourceAttributeIncludeFilter {
+		if val, ok := rm.Resource().Attributes().Get(attr); ok && !filter.Matches(val.AsString()) {
+			return
+		}
+	}
+	for attr, filter := range mb.resourceAttributeExcludeFilter {
+		if val, ok := rm.Resource().Attributes().Get(attr); ok && filter.Matches(val.AsString()) {
+			return
+		}
+	}
+
+	if ils.Metrics().Len() > 0 {
+		mb.updateCapacity(rm)
+		rm.MoveTo(mb.metricsBuffer.ResourceMetrics().AppendEmpty())
+	}
+}
+
+// Emit returns all the metrics accumulated by the metrics builder and updates the internal state to be ready for
+// recording another set of metrics. This function will be responsible for applying all the transformations required to
+// produce metric representation defined in metadata and user config, e.g. delta or cumulative.
+func (mb *MetricsBuilder) Emit(options ...ResourceMetricsOption) pmetric.Metrics {
+	mb.EmitForResource(options...)
+	metrics := mb.metricsBuffer
+	mb.metricsBuffer = pmetric.NewMetrics()
+	return metrics
+}
+
+// RecordPingDNSDurationDataPoint adds a data point to ping.dns.duration metric.
+func (mb *MetricsBuilder) RecordPingDNSDurationDataPoint(ts pcommon.Timestamp, val float64, netPeerNameAttributeValue string, dnsLookupTypeAttributeValue AttributeDNSLookupType, tagAttributeValue string) {
+	mb.metricPingDNSDuration.recordDataPoint(mb.startTime, ts, val, netPeerNameAttributeValue, dnsLookupTypeAttributeValue.String(), tagAttributeValue)
+}
+
+// RecordPingDNSErrorsDataPoint adds a data point to ping.dns.errors metric.
+func (mb *MetricsBuilder) RecordPingDNSErrorsDataPoint(ts pcommon.Timestamp, val int64, netPeerNameAttributeValue string, dnsLookupTypeAttributeValue AttributeDNSLookupType, errorTypeAttributeValue AttributeErrorType, tagAttributeValue string) {
+	mb.metricPingDNSErrors.recordDataPoint(mb.startTime, ts, val, netPeerNameAttributeValue, dnsLookupTypeAttributeValue.String(), errorTypeAttributeValue.String(), tagAttributeValue)
+}
+
+// RecordPingIpdvAvgDataPoint adds a data point to ping.ipdv.avg metric.
+func (mb *MetricsBuilder) RecordPingIpdvAvgDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingIpdvAvg.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingIpdvMaxDataPoint adds a data point to ping.ipdv.max metric.
+func (mb *MetricsBuilder) RecordPingIpdvMaxDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingIpdvMax.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingIpdvMinDataPoint adds a data point to ping.ipdv.min metric.
+func (mb *MetricsBuilder) RecordPingIpdvMinDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingIpdvMin.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingJitterDataPoint adds a data point to ping.jitter metric.
+func (mb *MetricsBuilder) RecordPingJitterDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingJitter.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingLossRatioDataPoint adds a data point to ping.loss.ratio metric.
+func (mb *MetricsBuilder) RecordPingLossRatioDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingLossRatio.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingPacketsDuplicateDataPoint adds a data point to ping.packets.duplicate metric.
+func (mb *MetricsBuilder) RecordPingPacketsDuplicateDataPoint(ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingPacketsDuplicate.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingPacketsReceivedDataPoint adds a data point to ping.packets.received metric.
+func (mb *MetricsBuilder) RecordPingPacketsReceivedDataPoint(ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingPacketsReceived.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingPacketsSentDataPoint adds a data point to ping.packets.sent metric.
+func (mb *MetricsBuilder) RecordPingPacketsSentDataPoint(ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingPacketsSent.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingReachableDataPoint adds a data point to ping.reachable metric.
+func (mb *MetricsBuilder) RecordPingReachableDataPoint(ts pcommon.Timestamp, val int64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingReachable.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttDataPoint adds a data point to ping.rtt metric.
+func (mb *MetricsBuilder) RecordPingRttDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRtt.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttAvgDataPoint adds a data point to ping.rtt.avg metric.
+func (mb *MetricsBuilder) RecordPingRttAvgDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttAvg.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttMaxDataPoint adds a data point to ping.rtt.max metric.
+func (mb *MetricsBuilder) RecordPingRttMaxDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttMax.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttMinDataPoint adds a data point to ping.rtt.min metric.
+func (mb *MetricsBuilder) RecordPingRttMinDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttMin.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttP50DataPoint adds a data point to ping.rtt.p50 metric.
+func (mb *MetricsBuilder) RecordPingRttP50DataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttP50.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttP90DataPoint adds a data point to ping.rtt.p90 metric.
+func (mb *MetricsBuilder) RecordPingRttP90DataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttP90.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttP99DataPoint adds a data point to ping.rtt.p99 metric.
+func (mb *MetricsBuilder) RecordPingRttP99DataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttP99.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttStddevDataPoint adds a data point to ping.rtt.stddev metric.
+func (mb *MetricsBuilder) RecordPingRttStddevDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttStddev.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// RecordPingRttTrimmedMeanDataPoint adds a data point to ping.rtt.trimmed_mean metric.
+func (mb *MetricsBuilder) RecordPingRttTrimmedMeanDataPoint(ts pcommon.Timestamp, val float64, netPeerIPAttributeValue string, netPeerNameAttributeValue string, netSockFamilyAttributeValue AttributeNetSockFamily, tagAttributeValue string, options ...MetricAttributeOption) {
+	mb.metricPingRttTrimmedMean.recordDataPoint(mb.startTime, ts, val, netPeerIPAttributeValue, netPeerNameAttributeValue, netSockFamilyAttributeValue.String(), tagAttributeValue, options...)
+}
+
+// Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
+// and metrics builder should update its startTime and reset it's internal state accordingly.
+func (mb *MetricsBuilder) Reset(options ...MetricBuilderOption) {
+	mb.startTime = pcommon.NewTimestampFromTime(time.Now())
+	for _, op := range options {
+		op.apply(mb)
+	}
+}
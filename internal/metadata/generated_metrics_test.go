@@ -0,0 +1,823 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type testDataSet int
+
+const (
+	testDataSetDefault testDataSet = iota
+	testDataSetAll
+	testDataSetNone
+)
+
+func TestMetricsBuilder(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricsSet  testDataSet
+		resAttrsSet testDataSet
+		expectEmpty bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:        "all_set",
+			metricsSet:  testDataSetAll,
+			resAttrsSet: testDataSetAll,
+		},
+		{
+			name:        "none_set",
+			metricsSet:  testDataSetNone,
+			resAttrsSet: testDataSetNone,
+			expectEmpty: true,
+		},
+		{
+			name:        "filter_set_include",
+			resAttrsSet: testDataSetAll,
+		},
+		{
+			name:        "filter_set_exclude",
+			resAttrsSet: testDataSetAll,
+			expectEmpty: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := pcommon.Timestamp(1_000_000_000)
+			ts := pcommon.Timestamp(1_000_001_000)
+			observedZapCore, observedLogs := observer.New(zap.WarnLevel)
+			settings := receivertest.NewNopSettings(receivertest.NopType)
+			settings.Logger = zap.New(observedZapCore)
+			mb := NewMetricsBuilder(loadMetricsBuilderConfig(t, tt.name), settings, WithStartTime(start))
+
+			expectedWarnings := 0
+
+			assert.Equal(t, expectedWarnings, observedLogs.Len())
+
+			defaultMetricsCount := 0
+			allMetricsCount := 0
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingDNSDurationDataPoint(ts, 1, "net.peer.name-val", AttributeDNSLookupTypeIp, "tag-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingDNSErrorsDataPoint(ts, 1, "net.peer.name-val", AttributeDNSLookupTypeIp, AttributeErrorTypeNotFound, "tag-val")
+
+			allMetricsCount++
+			mb.RecordPingIpdvAvgDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			allMetricsCount++
+			mb.RecordPingIpdvMaxDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			allMetricsCount++
+			mb.RecordPingIpdvMinDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingJitterDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingLossRatioDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingPacketsDuplicateDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingPacketsReceivedDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingPacketsSentDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingReachableDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttAvgDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttMaxDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttMinDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttP50DataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttP90DataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttP99DataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordPingRttStddevDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			allMetricsCount++
+			mb.RecordPingRttTrimmedMeanDataPoint(ts, 1, "net.peer.ip-val", "net.peer.name-val", AttributeNetSockFamilyInet, "tag-val", WithNetPeerPrefixMetricAttribute("net.peer.prefix-val"), WithNetHostIPMetricAttribute("net.host.ip-val"), WithNetHostInterfaceMetricAttribute("net.host.interface-val"))
+
+			rb := mb.NewResourceBuilder()
+			rb.SetHostName("host.name-val")
+			rb.SetIcmpcheckReceiverName("icmpcheck.receiver.name-val")
+			res := rb.Emit()
+			metrics := mb.Emit(WithResource(res))
+
+			if tt.expectEmpty {
+				assert.Equal(t, 0, metrics.ResourceMetrics().Len())
+				return
+			}
+
+			assert.Equal(t, 1, metrics.ResourceMetrics().Len())
+			rm := metrics.ResourceMetrics().At(0)
+			assert.Equal(t, res, rm.Resource())
+			assert.Equal(t, 1, rm.ScopeMetrics().Len())
+			ms := rm.ScopeMetrics().At(0).Metrics()
+			if tt.metricsSet == testDataSetDefault {
+				assert.Equal(t, defaultMetricsCount, ms.Len())
+			}
+			if tt.metricsSet == testDataSetAll {
+				assert.Equal(t, allMetricsCount, ms.Len())
+			}
+			validatedMetrics := make(map[string]bool)
+			for i := 0; i < ms.Len(); i++ {
+				switch ms.At(i).Name() {
+				case "ping.dns.duration":
+					assert.False(t, validatedMetrics["ping.dns.duration"], "Found a duplicate in the metrics slice: ping.dns.duration")
+					validatedMetrics["ping.dns.duration"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Time spent resolving the host name of a target during a scrape. Answers served from the cache take close to 0.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("dns.lookup.type")
+					assert.True(t, ok)
+					assert.Equal(t, "ip", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.dns.errors":
+					assert.False(t, validatedMetrics["ping.dns.errors"], "Found a duplicate in the metrics slice: ping.dns.errors")
+					validatedMetrics["ping.dns.errors"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Number of failed lookups of the host name of a target since the receiver started.", ms.At(i).Description())
+					assert.Equal(t, "{error}", ms.At(i).Unit())
+					assert.True(t, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("dns.lookup.type")
+					assert.True(t, ok)
+					assert.Equal(t, "ip", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("error.type")
+					assert.True(t, ok)
+					assert.Equal(t, "not_found", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.ipdv.avg":
+					assert.False(t, validatedMetrics["ping.ipdv.avg"], "Found a duplicate in the metrics slice: ping.ipdv.avg")
+					validatedMetrics["ping.ipdv.avg"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Average absolute IP packet delay variation (RFC 3393) between echo requests of consecutive sequence numbers during a scrape.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.ipdv.max":
+					assert.False(t, validatedMetrics["ping.ipdv.max"], "Found a duplicate in the metrics slice: ping.ipdv.max")
+					validatedMetrics["ping.ipdv.max"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Maximum IP packet delay variation (RFC 3393) between echo requests of consecutive sequence numbers during a scrape.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.ipdv.min":
+					assert.False(t, validatedMetrics["ping.ipdv.min"], "Found a duplicate in the metrics slice: ping.ipdv.min")
+					validatedMetrics["ping.ipdv.min"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Minimum IP packet delay variation (RFC 3393) between echo requests of consecutive sequence numbers during a scrape. Negative when a reply came back faster than the previous one.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.jitter":
+					assert.False(t, validatedMetrics["ping.jitter"], "Found a duplicate in the metrics slice: ping.jitter")
+					validatedMetrics["ping.jitter"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Interarrival jitter of the echo replies received during a scrape, as defined by RFC 3550, computed from the round-trip times of consecutive replies. Requires at least 2 replies.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.loss.ratio":
+					assert.False(t, validatedMetrics["ping.loss.ratio"], "Found a duplicate in the metrics slice: ping.loss.ratio")
+					validatedMetrics["ping.loss.ratio"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Ratio of echo requests sent during a scrape that got no reply, between 0 and 1.", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.packets.duplicate":
+					assert.False(t, validatedMetrics["ping.packets.duplicate"], "Found a duplicate in the metrics slice: ping.packets.duplicate")
+					validatedMetrics["ping.packets.duplicate"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Number of duplicate echo replies received from the target since it was first pinged.", ms.At(i).Description())
+					assert.Equal(t, "{packet}", ms.At(i).Unit())
+					assert.True(t, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.packets.received":
+					assert.False(t, validatedMetrics["ping.packets.received"], "Found a duplicate in the metrics slice: ping.packets.received")
+					validatedMetrics["ping.packets.received"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Number of echo replies received from the target since it was first pinged, duplicates excluded.", ms.At(i).Description())
+					assert.Equal(t, "{packet}", ms.At(i).Unit())
+					assert.True(t, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.packets.sent":
+					assert.False(t, validatedMetrics["ping.packets.sent"], "Found a duplicate in the metrics slice: ping.packets.sent")
+					validatedMetrics["ping.packets.sent"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Number of echo requests sent to the target since it was first pinged.", ms.At(i).Description())
+					assert.Equal(t, "{packet}", ms.At(i).Unit())
+					assert.True(t, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.reachable":
+					assert.False(t, validatedMetrics["ping.reachable"], "Found a duplicate in the metrics slice: ping.reachable")
+					validatedMetrics["ping.reachable"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Whether the target answered at least one echo request during a scrape (1) or not (0). Targets that could not be pinged are reported unreachable, with an empty net.peer.ip when their host name cannot be resolved.", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt":
+					assert.False(t, validatedMetrics["ping.rtt"], "Found a duplicate in the metrics slice: ping.rtt")
+					validatedMetrics["ping.rtt"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Round-trip time of a single echo reply. One data point is recorded per received packet.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.avg":
+					assert.False(t, validatedMetrics["ping.rtt.avg"], "Found a duplicate in the metrics slice: ping.rtt.avg")
+					validatedMetrics["ping.rtt.avg"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Average round-trip time of the echo replies received during a scrape.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.max":
+					assert.False(t, validatedMetrics["ping.rtt.max"], "Found a duplicate in the metrics slice: ping.rtt.max")
+					validatedMetrics["ping.rtt.max"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Maximum round-trip time of the echo replies received during a scrape.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.min":
+					assert.False(t, validatedMetrics["ping.rtt.min"], "Found a duplicate in the metrics slice: ping.rtt.min")
+					validatedMetrics["ping.rtt.min"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Minimum round-trip time of the echo replies received during a scrape.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.p50":
+					assert.False(t, validatedMetrics["ping.rtt.p50"], "Found a duplicate in the metrics slice: ping.rtt.p50")
+					validatedMetrics["ping.rtt.p50"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Median round-trip time of the echo replies received during a scrape, interpolated between the closest replies.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.p90":
+					assert.False(t, validatedMetrics["ping.rtt.p90"], "Found a duplicate in the metrics slice: ping.rtt.p90")
+					validatedMetrics["ping.rtt.p90"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "90th percentile of the round-trip time of the echo replies received during a scrape, interpolated between the closest replies.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.p99":
+					assert.False(t, validatedMetrics["ping.rtt.p99"], "Found a duplicate in the metrics slice: ping.rtt.p99")
+					validatedMetrics["ping.rtt.p99"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "99th percentile of the round-trip time of the echo replies received during a scrape, interpolated between the closest replies.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.stddev":
+					assert.False(t, validatedMetrics["ping.rtt.stddev"], "Found a duplicate in the metrics slice: ping.rtt.stddev")
+					validatedMetrics["ping.rtt.stddev"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Standard deviation of the round-trip time of the echo replies received during a scrape.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				case "ping.rtt.trimmed_mean":
+					assert.False(t, validatedMetrics["ping.rtt.trimmed_mean"], "Found a duplicate in the metrics slice: ping.rtt.trimmed_mean")
+					validatedMetrics["ping.rtt.trimmed_mean"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Average round-trip time of the echo replies received during a scrape, once the `rtt_trim_ratio` fastest and slowest replies are dropped.", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("net.peer.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.name")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.name-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.peer.prefix")
+					assert.True(t, ok)
+					assert.Equal(t, "net.peer.prefix-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.sock.family")
+					assert.True(t, ok)
+					assert.Equal(t, "inet", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.ip")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.ip-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("net.host.interface")
+					assert.True(t, ok)
+					assert.Equal(t, "net.host.interface-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("tag")
+					assert.True(t, ok)
+					assert.Equal(t, "tag-val", attrVal.Str())
+				}
+			}
+		})
+	}
+}
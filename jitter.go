@@ -0,0 +1,82 @@
+package icmpreceiver
+
+import (
+	"math"
+	"slices"
+)
+
+// jitterGain is the weight of each new transit time difference in the RFC 3550
+// interarrival jitter estimate.
+const jitterGain = 1. / 16
+
+// ipdvStats are the statistics of the IP packet delay variation (RFC 3393) of
+// consecutive packets, in milliseconds.
+type ipdvStats struct {
+	min, max float64
+	// meanAbs is the average absolute delay variation. The average of the
+	// signed variations would only depend on the first and last packets.
+	meanAbs float64
+}
+
+// sortedBySeq returns the round-trip times of the packets in milliseconds,
+// with their sequence numbers, in sequence order.
+func sortedBySeq(packets []*Packet) (seqs []int, rtts []float64) {
+	sorted := slices.Clone(packets)
+	slices.SortFunc(sorted, func(a, b *Packet) int { return a.Seq - b.Seq })
+
+	seqs = make([]int, 0, len(sorted))
+	rtts = make([]float64, 0, len(sorted))
+	for _, pkt := range sorted {
+		seqs = append(seqs, pkt.Seq)
+		rtts = append(rtts, durationMs(pkt.Rtt))
+	}
+	return seqs, rtts
+}
+
+// interarrivalJitter returns the RFC 3550 interarrival jitter of the packets,
+// in milliseconds. Round-trip times stand in for transit times: the unknown
+// clock offset between the hosts cancels out in their differences. Lost
+// packets are skipped, as by an RTP receiver. It needs at least 2 packets.
+func interarrivalJitter(packets []*Packet) (float64, bool) {
+	if len(packets) < 2 {
+		return 0, false
+	}
+
+	_, rtts := sortedBySeq(packets)
+	var jitter float64
+	for i := 1; i < len(rtts); i++ {
+		jitter += (math.Abs(rtts[i]-rtts[i-1]) - jitter) * jitterGain
+	}
+	return jitter, true
+}
+
+// delayVariation returns the statistics of the RFC 3393 delay variation of the
+// pairs of packets with consecutive sequence numbers. It needs at least one
+// such pair.
+func delayVariation(packets []*Packet) (ipdvStats, bool) {
+	seqs, rtts := sortedBySeq(packets)
+
+	var (
+		stats ipdvStats
+		pairs int
+	)
+	for i := 1; i < len(rtts); i++ {
+		if seqs[i] != seqs[i-1]+1 {
+			continue
+		}
+		ipdv := rtts[i] - rtts[i-1]
+		if pairs == 0 || ipdv < stats.min {
+			stats.min = ipdv
+		}
+		if pairs == 0 || ipdv > stats.max {
+			stats.max = ipdv
+		}
+		stats.meanAbs += math.Abs(ipdv)
+		pairs++
+	}
+	if pairs == 0 {
+		return ipdvStats{}, false
+	}
+	stats.meanAbs /= float64(pairs)
+	return stats, true
+}
@@ -0,0 +1,110 @@
+package icmpreceiver
+
+import (
+	"testing"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+	"github.com/stretchr/testify/assert"
+)
+
+// packetsOf returns received packets with the given sequence numbers and
+// round-trip times in milliseconds.
+func packetsOf(seqs []int, rttsMs []float64) []*Packet {
+	packets := make([]*Packet, 0, len(seqs))
+	for i, seq := range seqs {
+		packets = append(
+			packets, &Packet{Packet: &probing.Packet{Seq: seq, Rtt: time.Duration(rttsMs[i] * float64(time.Millisecond))}},
+		)
+	}
+	return packets
+}
+
+func TestInterarrivalJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		packets []*Packet
+		want    float64
+		wantOK  bool
+	}{
+		{
+			name:    "no packet",
+			packets: nil,
+		},
+		{
+			name:    "single packet",
+			packets: packetsOf([]int{0}, []float64{10}),
+		},
+		{
+			name:    "constant round-trip time",
+			packets: packetsOf([]int{0, 1, 2}, []float64{10, 10, 10}),
+			want:    0,
+			wantOK:  true,
+		},
+		{
+			// J1 = 16/16 = 1, J2 = 1 + (8-1)/16 = 1.4375
+			name:    "varying round-trip time",
+			packets: packetsOf([]int{0, 1, 2}, []float64{10, 26, 18}),
+			want:    1.4375,
+			wantOK:  true,
+		},
+		{
+			name:    "packets sorted by sequence",
+			packets: packetsOf([]int{2, 0, 1}, []float64{18, 10, 26}),
+			want:    1.4375,
+			wantOK:  true,
+		},
+		{
+			name:    "lost packets skipped",
+			packets: packetsOf([]int{0, 3}, []float64{10, 42}),
+			want:    2,
+			wantOK:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := interarrivalJitter(tt.packets)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestDelayVariation(t *testing.T) {
+	tests := []struct {
+		name    string
+		packets []*Packet
+		want    ipdvStats
+		wantOK  bool
+	}{
+		{
+			name:    "single packet",
+			packets: packetsOf([]int{0}, []float64{10}),
+		},
+		{
+			name:    "no consecutive packets",
+			packets: packetsOf([]int{0, 2, 4}, []float64{10, 20, 30}),
+		},
+		{
+			name:    "consecutive packets",
+			packets: packetsOf([]int{0, 1, 2, 3}, []float64{10, 16, 12, 13}),
+			want:    ipdvStats{min: -4, max: 6, meanAbs: 11. / 3},
+			wantOK:  true,
+		},
+		{
+			name:    "pairs around lost packets skipped",
+			packets: packetsOf([]int{3, 0, 1, 5}, []float64{50, 10, 12, 1}),
+			want:    ipdvStats{min: 2, max: 2, meanAbs: 2},
+			wantOK:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := delayVariation(tt.packets)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.want.min, got.min, 1e-9)
+			assert.InDelta(t, tt.want.max, got.max, 1e-9)
+			assert.InDelta(t, tt.want.meanAbs, got.meanAbs, 1e-9)
+		})
+	}
+}
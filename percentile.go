@@ -0,0 +1,32 @@
+package icmpreceiver
+
+import "math"
+
+// defaultRttTrimRatio is the default share of the round-trip times dropped at
+// each end before computing ping.rtt.trimmed_mean.
+const defaultRttTrimRatio = 0.1
+
+// percentile returns the p-th percentile, between 0 and 1, of sorted values,
+// interpolating linearly between the closest ranks. sorted must not be empty.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// trimmedMean returns the mean of sorted values once the given ratio of them,
+// below 0.5, is dropped at each end. Whole values only are dropped, so small
+// samples may keep all their values. sorted must not be empty.
+func trimmedMean(sorted []float64, ratio float64) float64 {
+	trim := int(float64(len(sorted)) * ratio)
+	kept := sorted[trim : len(sorted)-trim]
+
+	var sum float64
+	for _, v := range kept {
+		sum += v
+	}
+	return sum / float64(len(kept))
+}
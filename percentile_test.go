@@ -0,0 +1,65 @@
+package icmpreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 12, 14, 16}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 10},
+		{p: .5, want: 13},
+		{p: .9, want: 15.4},
+		{p: .99, want: 15.94},
+		{p: 1, want: 16},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, percentile(sorted, tt.p), 1e-9, "p%v", tt.p*100)
+	}
+
+	assert.InDelta(t, 7., percentile([]float64{7}, .99), 1e-9)
+}
+
+func TestTrimmedMean(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		ratio  float64
+		want   float64
+	}{
+		{
+			name:   "no trimming",
+			sorted: []float64{10, 12, 14, 100},
+			ratio:  0,
+			want:   34,
+		},
+		{
+			name:   "too few values to trim",
+			sorted: []float64{10, 12, 14, 100},
+			ratio:  .1,
+			want:   34,
+		},
+		{
+			name:   "outliers dropped",
+			sorted: []float64{1, 10, 10, 11, 11, 12, 12, 13, 13, 250},
+			ratio:  .1,
+			want:   11.5,
+		},
+		{
+			name:   "single value kept",
+			sorted: []float64{1, 5, 900},
+			ratio:  .49,
+			want:   5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, trimmedMean(tt.sorted, tt.ratio), 1e-9)
+		})
+	}
+}
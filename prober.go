@@ -0,0 +1,191 @@
+package icmpreceiver
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+	"golang.org/x/net/icmp"
+)
+
+// Prober sends ICMP echo requests to a single target and reports the packets
+// received along with the final statistics of the run.
+type Prober interface {
+	Probe(ctx context.Context, req ProbeRequest) (*ProbeResult, error)
+}
+
+// StreamProber is a Prober that can also ping a target continuously, for the
+// continuous mode. The default prober implements it.
+type StreamProber interface {
+	Prober
+	// Stream sends echo requests every req.Interval until ctx ends, ignoring
+	// req.Count and req.Timeout, and reports packets to handler as they are
+	// sent and received.
+	Stream(ctx context.Context, req ProbeRequest, handler StreamHandler) error
+}
+
+// StreamHandler receives the packets of a continuous probe. Its methods may be
+// called concurrently.
+type StreamHandler interface {
+	OnSend(seq int, at time.Time)
+	OnRecv(pkt *Packet)
+	OnDuplicateRecv(pkt *Packet)
+}
+
+// ProbeRequest describes a single probe run against one target.
+type ProbeRequest struct {
+	Target  string
+	Count   int
+	Timeout time.Duration
+	// Privileged selects raw ICMP sockets instead of unprivileged datagram ones.
+	Privileged bool
+	// AddressFamily restricts name resolution to AddressFamilyIPv4 or
+	// AddressFamilyIPv6. AddressFamilyAny lets the resolver choose.
+	AddressFamily string
+	// Source is the IP address or the name of the interface to send from.
+	Source string
+	// PacketSize, TTL and Interval use the prober defaults when zero.
+	PacketSize int
+	TTL        int
+	Interval   time.Duration
+}
+
+// ProbeResult is the outcome of a single probe run.
+type ProbeResult struct {
+	Packets        []*Packet
+	Stats          *probing.Statistics
+	StatsTimestamp time.Time
+	// TimedOut is set when the context ended before the probe finished.
+	TimedOut bool
+}
+
+// socketChecker is implemented by probers that open ICMP sockets, to let the
+// receiver verify at start that the configured socket mode works on this host.
+type socketChecker interface {
+	CheckSocket(privileged bool, addressFamily string) error
+}
+
+// Packet is a received echo reply along with the time it was received.
+type Packet struct {
+	Timestamp time.Time
+	*probing.Packet
+}
+
+// proBingProber is the default Prober, backed by github.com/prometheus-community/pro-bing.
+type proBingProber struct{}
+
+func newProBingProber() StreamProber {
+	return proBingProber{}
+}
+
+func (proBingProber) Probe(ctx context.Context, req ProbeRequest) (*ProbeResult, error) {
+	pinger, err := newPinger(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ProbeResult{}
+
+	pinger.OnRecv = func(pkt *probing.Packet) {
+		res.Packets = append(
+			res.Packets,
+			&Packet{
+				Timestamp: time.Now(),
+				Packet:    pkt,
+			},
+		)
+	}
+
+	pinger.Count = req.Count
+	pinger.Timeout = req.Timeout
+
+	err = pinger.RunWithContext(ctx)
+	if err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("failed to run pinger: %w", err)
+	}
+
+	res.Stats = pinger.Statistics()
+	res.StatsTimestamp = time.Now()
+	res.TimedOut = ctx.Err() != nil
+
+	return res, nil
+}
+
+func (proBingProber) Stream(ctx context.Context, req ProbeRequest, handler StreamHandler) error {
+	pinger, err := newPinger(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	pinger.OnSend = func(pkt *probing.Packet) {
+		handler.OnSend(pkt.Seq, time.Now())
+	}
+	pinger.OnRecv = func(pkt *probing.Packet) {
+		handler.OnRecv(&Packet{Timestamp: time.Now(), Packet: pkt})
+	}
+	pinger.OnDuplicateRecv = func(pkt *probing.Packet) {
+		handler.OnDuplicateRecv(&Packet{Timestamp: time.Now(), Packet: pkt})
+	}
+	// Without a count, the pinger runs until ctx ends. The statistics of the
+	// whole run are not used, do not keep every round-trip time.
+	pinger.Count = -1
+	pinger.RecordRtts = false
+	pinger.RecordTTLs = false
+
+	if err := pinger.RunWithContext(ctx); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to run pinger: %w", err)
+	}
+	return nil
+}
+
+// newPinger returns a pinger for req, resolved and configured except for its
+// count, timeout and callbacks.
+func newPinger(ctx context.Context, req ProbeRequest) (*probing.Pinger, error) {
+	pinger := probing.New(req.Target)
+	pinger.SetNetwork(req.AddressFamily)
+	if deadline, ok := ctx.Deadline(); ok {
+		pinger.ResolveTimeout = time.Until(deadline)
+	}
+
+	if err := pinger.Resolve(); err != nil {
+		return nil, fmt.Errorf("failed to create pinger: %w", err)
+	}
+
+	pinger.SetPrivileged(req.Privileged)
+	if _, err := netip.ParseAddr(req.Source); err == nil {
+		pinger.Source = req.Source
+	} else {
+		pinger.InterfaceName = req.Source
+	}
+	if req.PacketSize > 0 {
+		pinger.Size = req.PacketSize
+	}
+	if req.TTL > 0 {
+		pinger.TTL = req.TTL
+	}
+	if req.Interval > 0 {
+		pinger.Interval = req.Interval
+	}
+	return pinger, nil
+}
+
+// CheckSocket opens and closes an ICMP socket in the given mode.
+func (proBingProber) CheckSocket(privileged bool, addressFamily string) error {
+	network, address := "udp4", ""
+	switch {
+	case privileged && addressFamily == AddressFamilyIPv6:
+		network, address = "ip6:ipv6-icmp", "::"
+	case privileged:
+		network, address = "ip4:icmp", "0.0.0.0"
+	case addressFamily == AddressFamilyIPv6:
+		network = "udp6"
+	}
+
+	conn, err := icmp.ListenPacket(network, address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
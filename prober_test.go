@@ -0,0 +1,281 @@
+package icmpreceiver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTimestamp is the time at which every fake probe starts.
+var fakeTimestamp = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeReply describes how fakeProber answers for a target.
+type fakeReply struct {
+	ip string
+	// ip6 is the address used when IPv6 is requested.
+	ip6 string
+	// rtts holds the round-trip time of every received packet, in order.
+	// Requested packets beyond len(rtts) are lost.
+	rtts []time.Duration
+	err  error
+	// block makes the probe wait until its context ends.
+	block bool
+	// delay is how long the probe takes, unless its context ends first.
+	delay time.Duration
+	// failAfter makes a continuous probe fail with err once it sent that
+	// many requests, instead of right away.
+	failAfter int
+}
+
+// fakeProber is a deterministic, in-memory Prober.
+type fakeProber struct {
+	replies map[string]fakeReply
+	// socketErrs is returned by CheckSocket, keyed by socket mode.
+	socketErrs map[bool]error
+
+	mu       sync.Mutex
+	requests []ProbeRequest
+	// running and maxRunning are the number of probes running, now and at
+	// most.
+	running, maxRunning int
+}
+
+func newFakeProber(replies map[string]fakeReply) *fakeProber {
+	return &fakeProber{replies: replies}
+}
+
+func (p *fakeProber) Probe(ctx context.Context, req ProbeRequest) (*ProbeResult, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	p.running++
+	p.maxRunning = max(p.maxRunning, p.running)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+
+	reply, ok := p.replies[req.Target]
+	if !ok {
+		// Targets resolved by the scraper are probed by address.
+		reply, ok = p.replyOf(req.Target)
+	}
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: req.Target, IsNotFound: true}
+	}
+	if reply.err != nil {
+		return nil, reply.err
+	}
+
+	ip := reply.ip
+	if req.AddressFamily == AddressFamilyIPv6 {
+		ip = reply.ip6
+	}
+	if addr, err := netip.ParseAddr(req.Target); err == nil {
+		ip = req.Target
+		if req.AddressFamily == AddressFamilyIPv4 && !addr.Is4() || req.AddressFamily == AddressFamilyIPv6 && !addr.Is6() {
+			ip = ""
+		}
+	}
+	if ip == "" {
+		return nil, &net.DNSError{Err: "no suitable address found", Name: req.Target, IsNotFound: true}
+	}
+	ipAddr := &net.IPAddr{IP: net.ParseIP(ip)}
+
+	if reply.block {
+		<-ctx.Done()
+		return &ProbeResult{
+			Stats:          fakeStatistics(req.Target, ipAddr, 1, nil),
+			StatsTimestamp: fakeTimestamp.Add(req.Timeout),
+			TimedOut:       true,
+		}, nil
+	}
+
+	sleepContext(ctx, reply.delay)
+
+	rtts := reply.rtts[:min(len(reply.rtts), req.Count)]
+
+	res := &ProbeResult{
+		Stats:          fakeStatistics(req.Target, ipAddr, req.Count, rtts),
+		StatsTimestamp: fakeTimestamp.Add(req.Timeout),
+	}
+	for seq, rtt := range rtts {
+		res.Packets = append(
+			res.Packets, &Packet{
+				Timestamp: fakeTimestamp.Add(time.Duration(seq)*time.Second + rtt),
+				Packet: &probing.Packet{
+					Rtt:    rtt,
+					IPAddr: ipAddr,
+					Addr:   ip,
+					Seq:    seq,
+				},
+			},
+		)
+	}
+
+	return res, nil
+}
+
+// Stream answers every echo request right away, with the round-trip times of
+// the reply of the target in turn, sending one request every req.Interval.
+func (p *fakeProber) Stream(ctx context.Context, req ProbeRequest, handler StreamHandler) error {
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	p.mu.Unlock()
+
+	reply, ok := p.replyOf(req.Target)
+	if !ok {
+		return fmt.Errorf("no reply for %q", req.Target)
+	}
+	if reply.err != nil && reply.failAfter == 0 {
+		return reply.err
+	}
+
+	ticker := time.NewTicker(req.Interval)
+	defer ticker.Stop()
+	ipAddr := &net.IPAddr{IP: net.ParseIP(req.Target)}
+	for seq := 0; ; seq++ {
+		if reply.failAfter > 0 && seq == reply.failAfter {
+			return reply.err
+		}
+		sent := time.Now()
+		handler.OnSend(seq, sent)
+		if len(reply.rtts) > 0 {
+			rtt := reply.rtts[seq%len(reply.rtts)]
+			handler.OnRecv(&Packet{
+				Timestamp: sent.Add(rtt),
+				Packet:    &probing.Packet{Rtt: rtt, IPAddr: ipAddr, Addr: req.Target, Seq: seq},
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// replyOf returns the reply of the target that has the given address.
+func (p *fakeProber) replyOf(addr string) (fakeReply, bool) {
+	for _, reply := range p.replies {
+		if reply.ip == addr || reply.ip6 == addr {
+			return reply, true
+		}
+	}
+	return fakeReply{}, false
+}
+
+// LookupNetIP resolves the targets of the replies to their ip and ip6
+// addresses, so fakeProber can stand in for the resolver as well.
+func (p *fakeProber) LookupNetIP(_ context.Context, network, host string) ([]netip.Addr, error) {
+	reply, ok := p.replies[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	var addrs []netip.Addr
+	if reply.ip != "" && network != "ip6" {
+		addrs = append(addrs, netip.MustParseAddr(reply.ip))
+	}
+	if reply.ip6 != "" && network != "ip4" {
+		addrs = append(addrs, netip.MustParseAddr(reply.ip6))
+	}
+	return addrs, nil
+}
+
+func (p *fakeProber) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (p *fakeProber) CheckSocket(privileged bool, _ string) error {
+	return p.socketErrs[privileged]
+}
+
+// probedTargets returns the targets probed so far, in no particular order.
+func (p *fakeProber) probedTargets() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	targets := make([]string, 0, len(p.requests))
+	for _, req := range p.requests {
+		targets = append(targets, req.Target)
+	}
+	return targets
+}
+
+// mostRunning returns the highest number of probes that ran at once.
+func (p *fakeProber) mostRunning() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maxRunning
+}
+
+// fakeStatistics computes statistics the same way pro-bing does.
+func fakeStatistics(addr string, ipAddr *net.IPAddr, sent int, rtts []time.Duration) *probing.Statistics {
+	stats := &probing.Statistics{
+		PacketsSent: sent,
+		PacketsRecv: len(rtts),
+		Addr:        addr,
+		IPAddr:      ipAddr,
+		Rtts:        rtts,
+	}
+	if sent > 0 {
+		stats.PacketLoss = float64(sent-len(rtts)) / float64(sent) * 100
+	}
+	if len(rtts) == 0 {
+		return stats
+	}
+
+	stats.MinRtt, stats.MaxRtt = rtts[0], rtts[0]
+	var sum time.Duration
+	for _, rtt := range rtts {
+		stats.MinRtt = min(stats.MinRtt, rtt)
+		stats.MaxRtt = max(stats.MaxRtt, rtt)
+		sum += rtt
+	}
+	stats.AvgRtt = sum / time.Duration(len(rtts))
+
+	var sumSquares float64
+	for _, rtt := range rtts {
+		diff := float64(rtt - stats.AvgRtt)
+		sumSquares += diff * diff
+	}
+	stats.StdDevRtt = time.Duration(math.Sqrt(sumSquares / float64(len(rtts))))
+
+	return stats
+}
+
+func TestFakeProber(t *testing.T) {
+	prober := newFakeProber(
+		map[string]fakeReply{
+			"8.8.8.8": {ip: "8.8.8.8", rtts: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}},
+		},
+	)
+
+	res, err := prober.Probe(context.Background(), ProbeRequest{Target: "8.8.8.8", Count: 4, Timeout: time.Second})
+	require.NoError(t, err)
+
+	assert.Len(t, res.Packets, 2)
+	assert.Equal(t, 4, res.Stats.PacketsSent)
+	assert.Equal(t, 2, res.Stats.PacketsRecv)
+	assert.InDelta(t, 50., res.Stats.PacketLoss, 1e-9)
+	assert.Equal(t, 10*time.Millisecond, res.Stats.MinRtt)
+	assert.Equal(t, 20*time.Millisecond, res.Stats.MaxRtt)
+	assert.Equal(t, 15*time.Millisecond, res.Stats.AvgRtt)
+	assert.Equal(t, 5*time.Millisecond, res.Stats.StdDevRtt)
+
+	_, err = prober.Probe(context.Background(), ProbeRequest{Target: "unknown.example", Count: 4})
+	var dnsErr *net.DNSError
+	assert.ErrorAs(t, err, &dnsErr)
+}
@@ -1,40 +1,87 @@
 package icmpreceiver
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/scraper/scrapererror"
 
-	probing "github.com/prometheus-community/pro-bing"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+
+	"github.com/supersun/otel-icmp-receiver/internal/metadata"
 )
 
 const (
-	AttrPeerIp   = "net.peer.ip"
-	AttrPeerName = "net.peer.name"
-	AttrTag      = "tag"
-	TagNotSet    = "NA"
+	AttrPeerIp        = "net.peer.ip"
+	AttrPeerName      = "net.peer.name"
+	AttrPeerPrefix    = "net.peer.prefix"
+	AttrSockFamily    = "net.sock.family"
+	AttrHostIp        = "net.host.ip"
+	AttrHostInterface = "net.host.interface"
+	AttrTag           = "tag"
+	TagNotSet         = "NA"
 )
 
-type packet struct {
-	Timestamp time.Time
-	*probing.Packet
+type pingResult struct {
+	*ProbeResult
+	tag        string
+	attributes map[string]string
+	source     string
+	// prefix is the CIDR prefix or range the target was expanded from.
+	prefix string
+	// start is the time the probe was started at.
+	start time.Time
+	// peerName is the host name the pinged address was resolved from, if any.
+	peerName string
+	// targetKey is the key of the configured target, to forget its packet
+	// counts once it is removed.
+	targetKey string
 }
 
-type pingResult struct {
-	Packets        []*packet
-	Stats          *probing.Statistics
-	StatsTimestamp time.Time
-	tag            string
+// targetResult is the outcome of pinging a single target, or one of the
+// addresses it resolved to.
+type targetResult struct {
+	target Target
+	// configured is the target the result was resolved from, as configured.
+	configured Target
+	// peerName is the host name target was resolved from, when its
+	// resolve_mode is all or srv.
+	peerName string
+	// addresses are all the addresses the host name resolved to, when only
+	// one of them is pinged.
+	addresses []string
+	pingRes   *pingResult
+	err       error
+}
+
+// describe returns the quoted target of the result, along with the address
+// pinged when it was resolved by the scraper.
+func (r targetResult) describe() string {
+	if r.peerName != "" {
+		return fmt.Sprintf("%q at %s", r.peerName, r.target.Target)
+	}
+	return strconv.Quote(r.target.Target)
 }
 
 type pingScraper struct {
+	id                 component.ID
 	logger             *zap.Logger
 	collectionInterval time.Duration
 
@@ -42,159 +89,1052 @@ type pingScraper struct {
 	defaultPingCount   int
 	defaultPingTimeout time.Duration
 	tag                string
+	privileged         bool
+	addressFamily      string
+	defaultPacketSize  int
+	defaultTTL         int
+	defaultInterval    time.Duration
+	source             string
+	prober             Prober
+	mb                 *metadata.MetricsBuilder
+	// resource holds the enabled resource attributes of the metrics and logs.
+	resource pcommon.Resource
+	// rttHistogram replaces the ping.rtt gauge when rtt_mode is a histogram.
+	rttHistogram *rttHistogram
+	// rttTrimRatio is the share of round-trip times dropped at each end for
+	// ping.rtt.trimmed_mean.
+	rttTrimRatio float64
+	buildInfo    component.BuildInfo
+	// registry is set when the targets must not be pinged by other receivers.
+	registry *targetRegistry
+	// resolver looks up the addresses of host names.
+	resolver Resolver
+	// dnsErrors counts the failed lookups since start, for ping.dns.errors.
+	dnsErrors map[dnsErrorKey]*dnsErrorCount
+	// packetCounts counts the packets of each series since it was first
+	// pinged, for the ping.packets.* sums.
+	packetCounts map[packetCountKey]*packetCounts
+	// seriesExpiry is the number of scrapes of its targets after which a
+	// series they no longer report is dropped, 0 to keep them.
+	seriesExpiry int
+	// cfg validates the targets read from targetsFile, which is nil when no
+	// targets_file is configured.
+	cfg         *Config
+	targetsFile *targetsFile
+	// scheduler pings the targets that have their own interval.
+	scheduler *scheduler
+	// slots bounds the targets pinged or resolved at once, by the scrapes and
+	// the scheduler alike, to max_concurrency. It is nil when unbounded.
+	slots chan struct{}
+	// mode is burst or continuous. In continuous mode, streams runs the
+	// long-lived probes of the targets.
+	mode    string
+	streams *streams
+	// spread is the receiver-wide spread of the start offsets of the probes,
+	// drawn by sampler.
+	spread  time.Duration
+	sampler *sampler
+	// logs receives a log record whenever the state of a target, tracked by
+	// states, changes. It is nil when no logs pipeline uses the receiver.
+	logs   consumer.Logs
+	states *stateTracker
+
+	// stopCtx is canceled on receiver shutdown to interrupt running pings.
+	stopCtx context.Context
+	stop    context.CancelFunc
 }
 
 func newPingScraper(
 	receiverCfg *Config,
 	settings receiver.Settings,
+	prober Prober,
 ) (*pingScraper, error) {
-	return &pingScraper{
+	targets, err := expandTargets(receiverCfg.Targets, receiverCfg.MaxTargetExpansion, receiverCfg.AddressFamily)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *targetsFile
+	if receiverCfg.TargetsFile != "" {
+		file = &targetsFile{path: receiverCfg.TargetsFile}
+	}
+
+	stopCtx, stop := context.WithCancel(context.Background())
+
+	seed := receiverCfg.Seed
+	if seed == 0 {
+		// Receivers draw different offsets and intervals unless seeded alike.
+		seed = rand.Uint64()
+	}
+
+	mbc := receiverCfg.MetricsBuilderConfig
+	var rttHist *rttHistogram
+	if receiverCfg.RttMode != "" && receiverCfg.RttMode != RttModeGauge && mbc.Metrics.PingRtt.Enabled {
+		rttHist = newRttHistogram(
+			receiverCfg.RttMode, receiverCfg.RttHistogramBuckets, receiverCfg.RttHistogramMaxBuckets,
+		)
+		// ping.rtt is reported as a histogram instead of one gauge data point per packet.
+		mbc.Metrics.PingRtt.Enabled = false
+	}
+
+	s := &pingScraper{
+		id:                 settings.ID,
 		logger:             settings.Logger,
 		collectionInterval: receiverCfg.CollectionInterval,
 
-		targets:            receiverCfg.Targets,
+		targets:            targets,
 		defaultPingCount:   receiverCfg.DefaultPingCount,
 		defaultPingTimeout: receiverCfg.DefaultPingTimeout,
 		tag:                receiverCfg.Tag,
-	}, nil
+		privileged:         receiverCfg.Privileged,
+		addressFamily:      receiverCfg.AddressFamily,
+		defaultPacketSize:  receiverCfg.DefaultPacketSize,
+		defaultTTL:         receiverCfg.DefaultTTL,
+		defaultInterval:    receiverCfg.DefaultPingInterval,
+		source:             receiverCfg.Source,
+		prober:             prober,
+		mb:                 metadata.NewMetricsBuilder(mbc, settings),
+		rttHistogram:       rttHist,
+		rttTrimRatio:       receiverCfg.RttTrimRatio,
+		buildInfo:          settings.BuildInfo,
+		resolver:           newResolver(receiverCfg.Resolver),
+		dnsErrors:          make(map[dnsErrorKey]*dnsErrorCount),
+		packetCounts:       make(map[packetCountKey]*packetCounts),
+		seriesExpiry:       receiverCfg.SeriesExpiryScrapes,
+		cfg:                receiverCfg,
+		targetsFile:        file,
+		mode:               receiverCfg.Mode,
+		spread:             receiverCfg.Spread,
+		sampler:            newSampler(seed),
+		states:             newStateTracker(receiverCfg.StateChanges),
+		stopCtx:            stopCtx,
+		stop:               stop,
+	}
+	if receiverCfg.MaxConcurrency > 0 {
+		s.slots = make(chan struct{}, receiverCfg.MaxConcurrency)
+	}
+	s.resource = s.newResource(settings)
+	s.scheduler = newScheduler(func(ctx context.Context, target Target) ([]targetResult, []dnsLookup) {
+		return s.pingTargets(ctx, []Target{target})
+	}, s.sampler, s.spread)
+	return s, nil
 }
 
-func (s *pingScraper) Scrape(_ context.Context) (pmetric.Metrics, error) {
-	metrics := pmetric.NewMetrics()
-	scopeMetrics := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+// newResource returns the resource of the metrics and logs, with the resource
+// attributes enabled in the config.
+func (s *pingScraper) newResource(settings receiver.Settings) pcommon.Resource {
+	rb := s.mb.NewResourceBuilder()
+	if s.cfg.MetricsBuilderConfig.ResourceAttributes.HostName.Enabled {
+		if hostname, err := os.Hostname(); err == nil {
+			rb.SetHostName(hostname)
+		} else {
+			s.logger.Warn("cannot get the host name, leaving host.name out", zap.Error(err))
+		}
+	}
+	rb.SetIcmpcheckReceiverName(settings.ID.String())
+	return rb.Emit()
+}
 
-	rttMetric := scopeMetrics.AppendEmpty()
-	rttMetric.SetName("ping.rtt")
-	rttMetric.SetUnit("ms")
-	rttMetricDataPoints := rttMetric.SetEmptyGauge().DataPoints()
+// start fails fast when the targets file is invalid or when the ICMP socket
+// mode used by any target does not work on this host, instead of failing every
+// scrape. It then claims the targets if they are exclusive, and starts pinging
+// the targets that have their own interval.
+func (s *pingScraper) start(ctx context.Context, _ component.Host) error {
+	if s.targetsFile != nil {
+		// Read the file again, the scraper may be restarted.
+		*s.targetsFile = targetsFile{path: s.targetsFile.path}
+		if err := s.reloadTargets(); err != nil {
+			return fmt.Errorf("cannot load targets file: %w", err)
+		}
+	}
 
-	minRttMetric := scopeMetrics.AppendEmpty()
-	minRttMetric.SetName("ping.rtt.min")
-	minRttMetric.SetUnit("ms")
-	minRttMetricDataPoints := minRttMetric.SetEmptyGauge().DataPoints()
+	if err := s.checkSockets(); err != nil {
+		return err
+	}
+	if err := s.claimTargets(); err != nil {
+		return err
+	}
 
-	maxRttMetric := scopeMetrics.AppendEmpty()
-	maxRttMetric.SetName("ping.rtt.max")
-	maxRttMetric.SetUnit("ms")
-	maxRttMetricDataPoints := maxRttMetric.SetEmptyGauge().DataPoints()
+	if s.mode == ModeContinuous {
+		streamProber, ok := s.prober.(StreamProber)
+		if !ok {
+			return fmt.Errorf("mode %q is not supported by the prober", ModeContinuous)
+		}
+		if s.streams == nil {
+			s.streams = newStreams(streamProber, s.sampler)
+		}
+		// Start the probes right away, the first scrape then reports their
+		// first window.
+		s.collectStreams(ctx)
+		return nil
+	}
 
-	avgRttMetric := scopeMetrics.AppendEmpty()
-	avgRttMetric.SetName("ping.rtt.avg")
-	avgRttMetric.SetUnit("ms")
-	avgRttMetricDataPoints := avgRttMetric.SetEmptyGauge().DataPoints()
+	_, scheduled := partitionTargets(s.targets)
+	s.scheduler.start(s.stopCtx, scheduled)
+	return nil
+}
 
-	stddevRttMetric := scopeMetrics.AppendEmpty()
-	stddevRttMetric.SetName("ping.rtt.stddev")
-	stddevRttMetric.SetUnit("ms")
-	stddevRttMetricDataPoints := stddevRttMetric.SetEmptyGauge().DataPoints()
+// checkSockets checks that the ICMP socket modes used by the targets work on
+// this host, when the prober opens sockets.
+func (s *pingScraper) checkSockets() error {
+	checker, ok := s.prober.(socketChecker)
+	if !ok {
+		return nil
+	}
 
-	lossRatioMetric := scopeMetrics.AppendEmpty()
-	lossRatioMetric.SetName("ping.loss.ratio")
-	lossRatioMetricDataPoints := lossRatioMetric.SetEmptyGauge().DataPoints()
+	// socketMode is an ICMP socket kind that some target needs to open.
+	type socketMode struct {
+		privileged    bool
+		addressFamily string
+	}
 
+	var modes []socketMode
 	for _, target := range s.targets {
-		pingRes, err := s.ping(target)
+		mode := socketMode{privileged: s.isPrivileged(target), addressFamily: AddressFamilyIPv4}
+		if target.addressFamily(s.addressFamily) == AddressFamilyIPv6 {
+			mode.addressFamily = AddressFamilyIPv6
+		}
+		if !slices.Contains(modes, mode) {
+			modes = append(modes, mode)
+		}
+	}
+
+	var errs error
+	for _, mode := range modes {
+		if err := checker.CheckSocket(mode.privileged, mode.addressFamily); err != nil {
+			errs = multierr.Append(errs, socketModeError(checker, mode.privileged, mode.addressFamily, err))
+		}
+	}
+	return errs
+}
+
+// claimTargets registers the targets of the scraper as its own, failing when
+// another receiver with exclusive targets already pings one of them.
+func (s *pingScraper) claimTargets() error {
+	if s.registry == nil {
+		return nil
+	}
+
+	return s.registry.claim(s.id, targetKeys(s.targets, s.addressFamily))
+}
+
+// targetKeys returns the keys of targets, as claimed in the registry.
+func targetKeys(targets []Target, defaultFamily string) []string {
+	keys := make([]string, 0, len(targets))
+	for _, target := range targets {
+		keys = append(keys, target.key(defaultFamily))
+	}
+	return keys
+}
+
+// socketModeError explains why the given socket mode failed and which mode, if
+// any, works on this host instead.
+func socketModeError(checker socketChecker, privileged bool, addressFamily string, err error) error {
+	otherErr := checker.CheckSocket(!privileged, addressFamily)
+
+	switch {
+	case otherErr != nil:
+		privilegedErr, unprivilegedErr := err, otherErr
+		if !privileged {
+			privilegedErr, unprivilegedErr = otherErr, err
+		}
+		return fmt.Errorf(
+			"no %s ICMP socket mode works on this host (privileged: %v, unprivileged: %v): "+
+				"grant the collector CAP_NET_RAW and set \"privileged: true\", "+
+				"or add its group to the net.ipv4.ping_group_range sysctl",
+			addressFamily, privilegedErr, unprivilegedErr,
+		)
+	case privileged:
+		return fmt.Errorf(
+			"privileged %s ICMP sockets are not permitted on this host (%w): "+
+				"set \"privileged: false\" to use unprivileged sockets, which work here, "+
+				"or grant the collector CAP_NET_RAW", addressFamily, err,
+		)
+	default:
+		return fmt.Errorf(
+			"unprivileged %s ICMP sockets are not permitted on this host (%w): "+
+				"set \"privileged: true\" to use raw sockets, which work here, "+
+				"or add the collector's group to the net.ipv4.ping_group_range sysctl", addressFamily, err,
+		)
+	}
+}
+
+func (s *pingScraper) Scrape(ctx context.Context) (pmetric.Metrics, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(s.stopCtx, cancel)()
+	if s.stopCtx.Err() != nil {
+		// AfterFunc cancels asynchronously, make sure no ping starts after shutdown.
+		cancel()
+	}
+
+	if s.targetsFile != nil {
+		if err := s.reloadTargets(); err != nil {
+			s.logger.Warn("cannot reload targets file, keeping the previous targets", zap.Error(err))
+		}
+	}
+
+	metrics := pmetric.NewMetrics()
+	var scrapeErrs scrapererror.ScrapeErrors
+	logs := plog.NewLogs()
+	scopeLogs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	scopeLogs.Scope().SetName(metadata.ScopeName)
+	scopeLogs.Scope().SetVersion(s.buildInfo.Version)
+
+	var (
+		results []targetResult
+		lookups []dnsLookup
+	)
+	if s.mode == ModeContinuous {
+		results, lookups = s.collectStreams(ctx)
+	} else {
+		perScrape, _ := partitionTargets(s.targets)
+		results, lookups = s.pingTargets(ctx, perScrape)
+		// Targets with their own interval are reported once their run completes.
+		scheduledResults, scheduledLookups := s.scheduler.collect()
+		results, lookups = append(results, scheduledResults...), append(lookups, scheduledLookups...)
+	}
+
+	// pinged and lookedUp hold the keys of the targets pinged and looked up,
+	// to expire the series they no longer report.
+	pinged, lookedUp := make(map[string]bool), make(map[string]bool)
+	for _, lookup := range lookups {
+		s.recordDNSLookup(lookup)
+		lookedUp[lookup.target.key(s.addressFamily)] = true
+
+		lookupMetrics := s.mb.Emit()
+		putAttributes(lookupMetrics, lookup.target.Attributes)
+		appendMetrics(metrics, lookupMetrics)
+	}
+
+	for _, result := range results {
+		target := result.target
+		pingRes, err := result.pingRes, result.err
+		if err == nil && pingRes == nil {
+			// Continuous probes that have not settled any request yet.
+			continue
+		}
 		if err != nil {
 			var dnsErr *net.DNSError
+			switch {
+			case errors.As(err, &dnsErr):
+				s.logger.Warn("cannot resolve target, reporting it unreachable", zap.Error(dnsErr))
+			case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+				// The target was not measured, which says nothing about its reachability.
+				scrapeErrs.AddPartial(1, fmt.Errorf("target %s timed out: %w", result.describe(), err))
+				continue
+			default:
+				scrapeErrs.AddPartial(1, fmt.Errorf("failed to execute pinger for target %s: %w", result.describe(), err))
+			}
 
-			if errors.As(err, &dnsErr) {
-				s.logger.Log(zap.WarnLevel, "skipping target", zap.Error(dnsErr))
+			// A continuous probe that failed still reports the packets it
+			// exchanged before.
+			if pingRes == nil || pingRes.ProbeResult == nil {
+				peerIP, peerName := s.recordUnreachable(result)
+				s.trackState(scopeLogs.LogRecords(), result, peerIP, peerName, false, 1, time.Now())
+
+				targetMetrics := s.mb.Emit()
+				putAttributes(targetMetrics, target.Attributes)
+				appendMetrics(metrics, targetMetrics)
 				continue
-			} else {
-				return pmetric.NewMetrics(), fmt.Errorf(
-					"failed to execute pinger for target %q: %w", target.Target, err,
-				)
 			}
 		}
-		pingRes.tag = s.tag
+		pingRes.tag = target.tag(s.tag)
+		pingRes.attributes = target.Attributes
+		pingRes.prefix = target.prefix
+		pingRes.peerName = result.peerName
+		pingRes.targetKey = result.configured.key(s.addressFamily)
+		if pingRes.TimedOut {
+			// Like a target cut off before it started, with the partial results it got.
+			scrapeErrs.AddPartial(
+				1, fmt.Errorf(
+					"target %s timed out after %d packets sent, %d received", result.describe(),
+					pingRes.Stats.PacketsSent, pingRes.Stats.PacketsRecv,
+				),
+			)
+		}
+
+		start := s.recordPingResult(pingRes)
+		pinged[pingRes.targetKey] = true
+		if !pingRes.TimedOut {
+			// The loss of a probe cut short does not tell the state of the target.
+			s.trackState(
+				scopeLogs.LogRecords(), result, pingRes.Stats.IPAddr.IP.String(), pingRes.peerNameAttr(),
+				pingRes.Stats.PacketsRecv > 0, pingRes.Stats.PacketLoss/100., pingRes.StatsTimestamp,
+			)
+		}
+
+		// Data points are emitted per target to attach its custom attributes.
+		targetMetrics := s.mb.Emit()
+		setSumStartTimestamps(targetMetrics, start)
+		putAttributes(targetMetrics, target.Attributes)
+		appendMetrics(metrics, targetMetrics)
+	}
+	s.expireSeries(pinged, lookedUp)
+
+	if s.rttHistogram != nil {
+		s.rttHistogram.emit(metrics, metadata.ScopeName, s.buildInfo.Version)
+	}
+	if metrics.ResourceMetrics().Len() > 0 {
+		s.resource.CopyTo(metrics.ResourceMetrics().At(0).Resource())
+	}
+	if s.logs != nil && logs.LogRecordCount() > 0 {
+		s.resource.CopyTo(logs.ResourceLogs().At(0).Resource())
+		if err := s.logs.ConsumeLogs(ctx, logs); err != nil {
+			s.logger.Error("cannot send state change logs", zap.Error(err))
+		}
+	}
+
+	// Failed targets are reported as a partial scrape error, so the metrics of
+	// healthy targets are still sent down the pipeline.
+	return metrics, scrapeErrs.Combine()
+}
+
+// pingTargets resolves the host names of the targets, then pings all their
+// addresses, at most max_concurrency at a time along with the scheduler. A
+// max_concurrency of 0 pings every target at once. The returned results are in the same order as
+// targets, addresses of a target in the order they resolved, regardless of
+// the order in which pings complete. The lookups done are returned along.
+// Pings of targets without their own interval start after a random offset
+// below their spread, the scheduler offsets the others.
+func (s *pingScraper) pingTargets(ctx context.Context, targets []Target) ([]targetResult, []dnsLookup) {
+	results, lookups := s.resolveTargets(ctx, targets)
+	// Offsets are drawn in the order of the results, to be reproducible.
+	now := time.Now()
+	starts := make([]time.Time, len(results))
+	for i, result := range results {
+		starts[i] = now
+		if result.err == nil && result.target.Interval == nil {
+			starts[i] = now.Add(s.sampler.offset(result.target.spread(s.spread)))
+		}
+	}
+	s.runAt(ctx, starts, func(i int) {
+		if results[i].err == nil {
+			results[i].pingRes, results[i].err = s.ping(ctx, results[i].target)
+		}
+	})
+	return results, lookups
+}
 
-		// Check if no packets were received (i.e., ping timed out or no response)
-		/*if len(pingRes.Packets) == 0 {
-			// If no packets were received, skip adding data points
-			s.logger.Warn("no packets received, skipping data points for target", zap.String("target", target.Target))
+// resolveTargets resolves the host names of the targets, using at most
+// max_concurrency workers at a time. Results are in the order of targets.
+func (s *pingScraper) resolveTargets(ctx context.Context, targets []Target) ([]targetResult, []dnsLookup) {
+	resolved := make([][]targetResult, len(targets))
+	lookups := make([][]dnsLookup, len(targets))
+	s.runConcurrently(ctx, len(targets), func(i int) {
+		resolved[i], lookups[i] = s.resolveTarget(ctx, targets[i])
+	})
+	return slices.Concat(resolved...), slices.Concat(lookups...)
+}
+
+// collectStreams resolves the host names of the targets, and returns the
+// window since the previous call of the continuous probe of every address.
+// Probes of new addresses are started, and probes of addresses no longer
+// resolved are stopped.
+func (s *pingScraper) collectStreams(ctx context.Context) ([]targetResult, []dnsLookup) {
+	results, lookups := s.resolveTargets(ctx, s.targets)
+
+	active := make(map[ProbeRequest]bool, len(results))
+	for i, result := range results {
+		if result.err != nil {
 			continue
-		}*/
+		}
+		req := s.probeRequest(result.target)
+		// Host names rotating their addresses keep their probe while its
+		// address is still among them.
+		for _, addr := range result.addresses {
+			running := req
+			running.Target = addr
+			if s.streams.has(running) {
+				req = running
+				break
+			}
+		}
+		results[i].target.Target = req.Target
+		active[req] = true
+		// Probes outlive scrapes, they run until shutdown.
+		results[i].pingRes, results[i].err = s.streams.collect(s.stopCtx, req, result.target.spread(s.spread))
+	}
+	s.streams.retain(active)
+	return results, lookups
+}
+
+// runConcurrently calls fn for every index below n, in order, each once a
+// slot is free, and waits for all calls to return.
+func (s *pingScraper) runConcurrently(ctx context.Context, n int, fn func(i int)) {
+	s.runAt(ctx, make([]time.Time, n), fn)
+}
+
+// runAt calls fn for every index of starts, in the order of their start time,
+// each once its start time is reached and a slot is free, and waits for all
+// calls to return. Calls wait for their start before taking a slot, so that a
+// late start does not hold back the calls after it. Once ctx ends, the
+// remaining calls are made without waiting, fn is expected to return right
+// away then.
+func (s *pingScraper) runAt(ctx context.Context, starts []time.Time, fn func(i int)) {
+	order := make([]int, len(starts))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return starts[a].Compare(starts[b]) })
 
-		// Add data points only if we got valid ping results
-		for _, pkt := range pingRes.Packets {
-			appendPacketDataPoint(rttMetricDataPoints, float64(pkt.Rtt.Nanoseconds())/1e6, pkt, pingRes)
+	var wg sync.WaitGroup
+	for _, i := range order {
+		if !sleepContext(ctx, time.Until(starts[i])) || !s.acquireSlot(ctx) {
+			fn(i)
+			continue
 		}
+		wg.Go(func() {
+			defer s.releaseSlot()
+			fn(i)
+		})
+	}
+	wg.Wait()
+}
 
-		appendStatsDataPoint(lossRatioMetricDataPoints, pingRes.Stats.PacketLoss/100., pingRes)
-		appendStatsDataPoint(minRttMetricDataPoints, float64(pingRes.Stats.MinRtt)/1e6, pingRes)
-		appendStatsDataPoint(maxRttMetricDataPoints, float64(pingRes.Stats.MaxRtt)/1e6, pingRes)
-		appendStatsDataPoint(avgRttMetricDataPoints, float64(pingRes.Stats.AvgRtt)/1e6, pingRes)
-		appendStatsDataPoint(stddevRttMetricDataPoints, float64(pingRes.Stats.StdDevRtt)/1e6, pingRes)
+// acquireSlot waits until fewer than max_concurrency targets are pinged or
+// resolved, by the scrapes and the scheduler alike, and takes a slot. It
+// returns false, without a slot, if ctx ends first.
+func (s *pingScraper) acquireSlot(ctx context.Context) bool {
+	if s.slots == nil {
+		return true
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
 	}
+}
 
-	return metrics, nil
+// releaseSlot frees a slot taken by acquireSlot.
+func (s *pingScraper) releaseSlot() {
+	if s.slots != nil {
+		<-s.slots
+	}
 }
 
-func appendPacketDataPoint(
-	metricDataPoints pmetric.NumberDataPointSlice,
-	value float64,
-	pkt *packet,
-	pingRes *pingResult,
-) {
+// recordPingResult records the round-trip time of every received packet, the
+// statistics of the run, and the packets exchanged with the target since it
+// was first pinged, which is returned as the start of the ping.packets.* sums.
+// The round-trip time and delay variation statistics are left out when no
+// reply was received, as they would read 0 ms.
+func (s *pingScraper) recordPingResult(pingRes *pingResult) pcommon.Timestamp {
 	stats := pingRes.Stats
-	dp := metricDataPoints.AppendEmpty()
-	dp.SetDoubleValue(value)
-	dp.SetTimestamp(pcommon.NewTimestampFromTime(pkt.Timestamp))
-	dp.Attributes().PutStr(AttrPeerIp, pkt.Addr)
-	dp.Attributes().PutStr(AttrPeerName, stats.Addr)
-	dp.Attributes().PutStr(AttrTag, pingRes.tag)
-}
-
-func appendStatsDataPoint(
-	metricDataPoints pmetric.NumberDataPointSlice,
-	value float64,
-	pingRes *pingResult,
+	hostIP, hostInterface := sourceAttributes(pingRes.source)
+	peerName := pingRes.peerNameAttr()
+
+	opts := conditionalAttributes(pingRes.prefix, hostIP, hostInterface)
+
+	for _, pkt := range pingRes.Packets {
+		s.mb.RecordPingRttDataPoint(
+			pcommon.NewTimestampFromTime(pkt.Timestamp), durationMs(pkt.Rtt),
+			pkt.Addr, peerName, sockFamily(pkt.IPAddr), pingRes.tag, opts...,
+		)
+	}
+
+	ts := pcommon.NewTimestampFromTime(pingRes.StatsTimestamp)
+	peerIP, family := stats.IPAddr.IP.String(), sockFamily(stats.IPAddr)
+
+	rtts := make([]float64, 0, len(pingRes.Packets))
+	for _, pkt := range pingRes.Packets {
+		rtts = append(rtts, durationMs(pkt.Rtt))
+	}
+	slices.Sort(rtts)
+
+	if s.rttHistogram != nil {
+		attrs := s.rttHistogram.record(pcommon.NewTimestampFromTime(pingRes.start), ts, rtts)
+		attrs.PutStr(AttrPeerIp, peerIP)
+		attrs.PutStr(AttrPeerName, peerName)
+		if pingRes.prefix != "" {
+			attrs.PutStr(AttrPeerPrefix, pingRes.prefix)
+		}
+		attrs.PutStr(AttrSockFamily, family.String())
+		if hostIP != "" {
+			attrs.PutStr(AttrHostIp, hostIP)
+		}
+		if hostInterface != "" {
+			attrs.PutStr(AttrHostInterface, hostInterface)
+		}
+		attrs.PutStr(AttrTag, pingRes.tag)
+		for key, value := range pingRes.attributes {
+			attrs.PutStr(key, value)
+		}
+	}
+
+	key := packetCountKey{peerIP, peerName, pingRes.prefix, pingRes.source, pingRes.tag}
+	counts, ok := s.packetCounts[key]
+	if !ok {
+		counts = &packetCounts{start: pcommon.NewTimestampFromTime(pingRes.start), seriesOwners: newSeriesOwners()}
+		s.packetCounts[key] = counts
+	}
+	counts.see(pingRes.targetKey)
+	counts.sent += int64(stats.PacketsSent)
+	counts.received += int64(stats.PacketsRecv)
+	counts.duplicate += int64(stats.PacketsRecvDuplicates)
+	s.mb.RecordPingPacketsSentDataPoint(
+		ts, counts.sent, peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	s.mb.RecordPingPacketsReceivedDataPoint(
+		ts, counts.received, peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	s.mb.RecordPingPacketsDuplicateDataPoint(
+		ts, counts.duplicate, peerIP, peerName, family, pingRes.tag, opts...,
+	)
+
+	var reachable int64
+	if stats.PacketsRecv > 0 {
+		reachable = 1
+	}
+	s.mb.RecordPingReachableDataPoint(
+		ts, reachable, peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	s.mb.RecordPingLossRatioDataPoint(
+		ts, stats.PacketLoss/100., peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	if reachable == 0 {
+		return counts.start
+	}
+	s.mb.RecordPingRttMinDataPoint(
+		ts, durationMs(stats.MinRtt), peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	s.mb.RecordPingRttMaxDataPoint(
+		ts, durationMs(stats.MaxRtt), peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	s.mb.RecordPingRttAvgDataPoint(
+		ts, durationMs(stats.AvgRtt), peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	s.mb.RecordPingRttStddevDataPoint(
+		ts, durationMs(stats.StdDevRtt), peerIP, peerName, family, pingRes.tag, opts...,
+	)
+	if len(rtts) > 0 {
+		s.mb.RecordPingRttP50DataPoint(
+			ts, percentile(rtts, .5), peerIP, peerName, family, pingRes.tag, opts...,
+		)
+		s.mb.RecordPingRttP90DataPoint(
+			ts, percentile(rtts, .9), peerIP, peerName, family, pingRes.tag, opts...,
+		)
+		s.mb.RecordPingRttP99DataPoint(
+			ts, percentile(rtts, .99), peerIP, peerName, family, pingRes.tag, opts...,
+		)
+		s.mb.RecordPingRttTrimmedMeanDataPoint(
+			ts, trimmedMean(rtts, s.rttTrimRatio), peerIP, peerName, family, pingRes.tag, opts...,
+		)
+	}
+
+	if jitter, ok := interarrivalJitter(pingRes.Packets); ok {
+		s.mb.RecordPingJitterDataPoint(
+			ts, jitter, peerIP, peerName, family, pingRes.tag, opts...,
+		)
+	}
+	if ipdv, ok := delayVariation(pingRes.Packets); ok {
+		s.mb.RecordPingIpdvMinDataPoint(
+			ts, ipdv.min, peerIP, peerName, family, pingRes.tag, opts...,
+		)
+		s.mb.RecordPingIpdvMaxDataPoint(
+			ts, ipdv.max, peerIP, peerName, family, pingRes.tag, opts...,
+		)
+		s.mb.RecordPingIpdvAvgDataPoint(
+			ts, ipdv.meanAbs, peerIP, peerName, family, pingRes.tag, opts...,
+		)
+	}
+	return counts.start
+}
+
+// peerNameAttr returns the net.peer.name of the result: the host name the
+// pinged address was resolved from, or the target as pinged.
+func (r *pingResult) peerNameAttr() string {
+	if r.peerName != "" {
+		return r.peerName
+	}
+	return r.Stats.Addr
+}
+
+// trackState updates the state of the target of result, and appends a log
+// record to records when it changes. It does nothing when no logs pipeline
+// uses the receiver.
+func (s *pingScraper) trackState(
+	records plog.LogRecordSlice, result targetResult, peerIP, peerName string, reachable bool, lossRatio float64,
+	at time.Time,
 ) {
-	dp := metricDataPoints.AppendEmpty()
-	dp.SetDoubleValue(value)
-	dp.SetTimestamp(pcommon.NewTimestampFromTime(pingRes.StatsTimestamp))
-	dp.Attributes().PutStr(AttrPeerIp, pingRes.Stats.IPAddr.IP.String())
-	dp.Attributes().PutStr(AttrPeerName, pingRes.Stats.Addr)
-	dp.Attributes().PutStr(AttrTag, pingRes.tag)
+	if s.logs == nil {
+		return
+	}
+
+	target := result.target
+	source, tag := target.source(s.source), target.tag(s.tag)
+	// Results of the same configured target share its state, whether its host
+	// name resolved or not.
+	key := stateKey{target: result.configured.key(s.addressFamily), peerName: peerName}
+	if mode := result.configured.ResolveMode; isHostName(result.configured.Target) &&
+		(mode == ResolveModeAll || mode == ResolveModeSRV) {
+		key.address = peerIP
+	}
+	change, ok := s.states.observe(key, reachable, lossRatio, at)
+	if !ok {
+		return
+	}
+
+	record := records.AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(change.at))
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	record.SetEventName("ping.state_change")
+	switch change.current {
+	case StateDown:
+		record.SetSeverityNumber(plog.SeverityNumberError)
+		record.SetSeverityText("ERROR")
+	case StateDegraded:
+		record.SetSeverityNumber(plog.SeverityNumberWarn)
+		record.SetSeverityText("WARN")
+	default:
+		record.SetSeverityNumber(plog.SeverityNumberInfo)
+		record.SetSeverityText("INFO")
+	}
+
+	body := fmt.Sprintf("target %q is %s, was %s", peerName, change.current, change.previous)
+	if change.outage > 0 {
+		body += fmt.Sprintf(", outage of %v", change.outage.Round(time.Millisecond))
+	}
+	record.Body().SetStr(body)
+
+	attrs := record.Attributes()
+	attrs.PutStr(AttrState, change.current)
+	attrs.PutStr(AttrStatePrevious, change.previous)
+	attrs.PutDouble(AttrLossRatio, lossRatio)
+	if change.previous == StateDown || change.previous == StateDegraded {
+		attrs.PutDouble(AttrOutageDuration, durationMs(change.outage))
+	}
+	attrs.PutStr(AttrPeerIp, peerIP)
+	attrs.PutStr(AttrPeerName, peerName)
+	if target.prefix != "" {
+		attrs.PutStr(AttrPeerPrefix, target.prefix)
+	}
+	hostIP, hostInterface := sourceAttributes(source)
+	if hostIP != "" {
+		attrs.PutStr(AttrHostIp, hostIP)
+	}
+	if hostInterface != "" {
+		attrs.PutStr(AttrHostInterface, hostInterface)
+	}
+	attrs.PutStr(AttrTag, tag)
+	for key, value := range target.Attributes {
+		attrs.PutStr(key, value)
+	}
 }
 
-func (s *pingScraper) ping(target Target) (*pingResult, error) {
-	pinger, err := probing.NewPinger(target.Target)
-	if err != nil {
-		return &pingResult{}, fmt.Errorf("failed to create pinger: %w", err)
+// packetCountKey identifies the ping.packets.* series of a target.
+type packetCountKey struct {
+	peerIP, peerName, prefix, source, tag string
+}
+
+// packetCounts are the packets exchanged with a target since it was first
+// pinged, and the targets that pinged it.
+type packetCounts struct {
+	seriesOwners
+	start                     pcommon.Timestamp
+	sent, received, duplicate int64
+}
+
+// seriesOwners are the keys of the targets that reported a cumulative series,
+// and the number of their scrapes that did not report it since it last was.
+type seriesOwners struct {
+	targets map[string]bool
+	seen    bool
+	missed  int
+}
+
+func newSeriesOwners() seriesOwners {
+	return seriesOwners{targets: make(map[string]bool)}
+}
+
+// see records that target reported the series in the current scrape.
+func (o *seriesOwners) see(target string) {
+	o.targets[target] = true
+	o.seen = true
+}
+
+// expire ends the current scrape, in which the targets of scraped were
+// reported, and returns whether the series missed limit scrapes of its
+// targets in a row. A limit of 0 never expires it.
+func (o *seriesOwners) expire(scraped map[string]bool, limit int) bool {
+	if o.seen {
+		o.seen, o.missed = false, 0
+		return false
 	}
+	for target := range o.targets {
+		if scraped[target] {
+			o.missed++
+			break
+		}
+	}
+	return limit > 0 && o.missed >= limit
+}
 
-	res := &pingResult{}
+// expireSeries drops the cumulative series that the targets pinged or looked
+// up have stopped reporting for seriesExpiry scrapes, e.g. since their host
+// name resolves to other addresses or their SRV records to other hosts.
+func (s *pingScraper) expireSeries(pinged, lookedUp map[string]bool) {
+	for key, counts := range s.packetCounts {
+		if counts.expire(pinged, s.seriesExpiry) {
+			delete(s.packetCounts, key)
+		}
+	}
+	for key, errs := range s.dnsErrors {
+		if errs.expire(lookedUp, s.seriesExpiry) {
+			delete(s.dnsErrors, key)
+		}
+	}
+}
 
-	pinger.OnRecv = func(pkt *probing.Packet) {
-		res.Packets = append(
-			res.Packets,
-			&packet{
-				Timestamp: time.Now(),
-				Packet:    pkt,
-			},
-		)
+// recordUnreachable records a target that could not be pinged as unreachable,
+// and returns its net.peer.ip and net.peer.name. net.peer.ip is empty when the
+// host name of the target could not be resolved.
+func (s *pingScraper) recordUnreachable(result targetResult) (peerIP, peerName string) {
+	target := result.target
+	hostIP, hostInterface := sourceAttributes(target.source(s.source))
+	peerName = cmp.Or(result.peerName, target.Target)
+	family := metadata.AttributeNetSockFamilyInet
+	if addr, err := netip.ParseAddr(target.Target); err == nil {
+		peerIP = target.Target
+		if !addr.Unmap().Is4() {
+			family = metadata.AttributeNetSockFamilyInet6
+		}
+	} else if target.addressFamily(s.addressFamily) == AddressFamilyIPv6 {
+		family = metadata.AttributeNetSockFamilyInet6
 	}
 
-	if target.PingCount != nil {
-		pinger.Count = *target.PingCount
-	} else {
-		pinger.Count = s.defaultPingCount
+	s.mb.RecordPingReachableDataPoint(
+		pcommon.NewTimestampFromTime(time.Now()), 0,
+		peerIP, peerName, family, target.tag(s.tag), conditionalAttributes(target.prefix, hostIP, hostInterface)...,
+	)
+	return peerIP, peerName
+}
+
+// dnsErrorKey identifies a ping.dns.errors series.
+type dnsErrorKey struct {
+	name       string
+	lookupType metadata.AttributeDNSLookupType
+	errorType  metadata.AttributeErrorType
+	tag        string
+}
+
+// dnsErrorCount is the number of failed lookups of a ping.dns.errors series
+// since start, and the targets that looked its name up.
+type dnsErrorCount struct {
+	seriesOwners
+	count int64
+}
+
+// recordDNSLookup records the duration of a lookup, and the number of failed
+// lookups of its name since start.
+func (s *pingScraper) recordDNSLookup(lookup dnsLookup) {
+	ts := pcommon.NewTimestampFromTime(time.Now())
+	tag := lookup.target.tag(s.tag)
+
+	s.mb.RecordPingDNSDurationDataPoint(ts, durationMs(lookup.duration), lookup.name, lookup.lookupType, tag)
+
+	if lookup.err != nil {
+		key := dnsErrorKey{lookup.name, lookup.lookupType, dnsErrorType(lookup.err), tag}
+		errs, ok := s.dnsErrors[key]
+		if !ok {
+			errs = &dnsErrorCount{seriesOwners: newSeriesOwners()}
+			s.dnsErrors[key] = errs
+		}
+		errs.count++
+	}
+	for key, errs := range s.dnsErrors {
+		if key.name == lookup.name && key.lookupType == lookup.lookupType && key.tag == tag {
+			errs.see(lookup.target.key(s.addressFamily))
+			s.mb.RecordPingDNSErrorsDataPoint(ts, errs.count, key.name, key.lookupType, key.errorType, key.tag)
+		}
+	}
+}
+
+// putAttributes adds the given attributes to every data point of metrics.
+func putAttributes(metrics pmetric.Metrics, attributes map[string]string) {
+	if len(attributes) == 0 {
+		return
 	}
 
-	if target.PingTimeout != nil {
-		pinger.Timeout = *target.PingTimeout
-	} else {
-		pinger.Timeout = s.defaultPingTimeout
+	forEachMetric(metrics, func(metric pmetric.Metric) {
+		var dps []pcommon.Map
+		switch metric.Type() {
+		case pmetric.MetricTypeGauge:
+			for _, dp := range metric.Gauge().DataPoints().All() {
+				dps = append(dps, dp.Attributes())
+			}
+		case pmetric.MetricTypeSum:
+			for _, dp := range metric.Sum().DataPoints().All() {
+				dps = append(dps, dp.Attributes())
+			}
+		}
+		for _, attrs := range dps {
+			for key, value := range attributes {
+				attrs.PutStr(key, value)
+			}
+		}
+	})
+}
+
+// appendMetrics moves the metrics of src to the first scope of dst, appending
+// the data points of metrics dst already has.
+func appendMetrics(dst, src pmetric.Metrics) {
+	if src.ResourceMetrics().Len() == 0 {
+		return
+	}
+	if dst.ResourceMetrics().Len() == 0 {
+		src.ResourceMetrics().MoveAndAppendTo(dst.ResourceMetrics())
+		return
 	}
 
-	err = pinger.Run()
+	dstMetrics := dst.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	forEachMetric(src, func(metric pmetric.Metric) {
+		for _, dstMetric := range dstMetrics.All() {
+			if dstMetric.Name() != metric.Name() {
+				continue
+			}
+			switch metric.Type() {
+			case pmetric.MetricTypeGauge:
+				metric.Gauge().DataPoints().MoveAndAppendTo(dstMetric.Gauge().DataPoints())
+			case pmetric.MetricTypeSum:
+				metric.Sum().DataPoints().MoveAndAppendTo(dstMetric.Sum().DataPoints())
+			}
+			return
+		}
+		metric.MoveTo(dstMetrics.AppendEmpty())
+	})
+}
+
+// forEachMetric calls fn for every metric of metrics.
+func forEachMetric(metrics pmetric.Metrics, fn func(pmetric.Metric)) {
+	for _, rm := range metrics.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, metric := range sm.Metrics().All() {
+				fn(metric)
+			}
+		}
+	}
+}
+
+// setSumStartTimestamps sets the start timestamp of the data points of every
+// sum of metrics.
+func setSumStartTimestamps(metrics pmetric.Metrics, start pcommon.Timestamp) {
+	forEachMetric(metrics, func(metric pmetric.Metric) {
+		if metric.Type() != pmetric.MetricTypeSum {
+			return
+		}
+		for _, dp := range metric.Sum().DataPoints().All() {
+			dp.SetStartTimestamp(start)
+		}
+	})
+}
+
+// sourceAttributes returns the net.host.ip and net.host.interface attribute
+// values of the configured source. Both are empty when no source is set.
+func sourceAttributes(source string) (hostIP, hostInterface string) {
+	if source == "" {
+		return "", ""
+	}
+	if _, err := netip.ParseAddr(source); err == nil {
+		return source, ""
+	}
+	return "", source
+}
+
+// conditionalAttributes returns the net.peer.prefix, net.host.ip and
+// net.host.interface attributes of a data point, leaving out the ones not set.
+func conditionalAttributes(prefix, hostIP, hostInterface string) []metadata.MetricAttributeOption {
+	var opts []metadata.MetricAttributeOption
+	if prefix != "" {
+		opts = append(opts, metadata.WithNetPeerPrefixMetricAttribute(prefix))
+	}
+	if hostIP != "" {
+		opts = append(opts, metadata.WithNetHostIPMetricAttribute(hostIP))
+	}
+	if hostInterface != "" {
+		opts = append(opts, metadata.WithNetHostInterfaceMetricAttribute(hostInterface))
+	}
+	return opts
+}
+
+// durationMs converts d to fractional milliseconds.
+func durationMs(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e6
+}
+
+// sockFamily returns the net.sock.family attribute value of the address.
+func sockFamily(addr *net.IPAddr) metadata.AttributeNetSockFamily {
+	if addr != nil && addr.IP.To4() == nil {
+		return metadata.AttributeNetSockFamilyInet6
+	}
+	return metadata.AttributeNetSockFamilyInet
+}
+
+// isPrivileged returns whether the target is pinged using raw ICMP sockets.
+func (s *pingScraper) isPrivileged(target Target) bool {
+	if target.Privileged != nil {
+		return *target.Privileged
+	}
+	return s.privileged
+}
+
+// shutdown interrupts any ping still running. It must be called before the
+// scraper controller is shut down, as the controller waits for running scrapes.
+func (s *pingScraper) shutdown(_ context.Context) error {
+	s.stop()
+	s.scheduler.stop()
+	if s.streams != nil {
+		s.streams.stop()
+	}
+	if s.registry != nil {
+		s.registry.release(s.id)
+	}
+	return nil
+}
+
+func (s *pingScraper) ping(ctx context.Context, target Target) (*pingResult, error) {
+	if err := ctx.Err(); err != nil {
+		return &pingResult{}, fmt.Errorf("pinger not started: %w", err)
+	}
+
+	req := s.probeRequest(target)
+	start := time.Now()
+	res, err := s.prober.Probe(ctx, req)
 	if err != nil {
-		return &pingResult{}, fmt.Errorf("failed to run pinger: %w", err)
+		return &pingResult{}, err
 	}
 
-	res.Stats = pinger.Statistics()
-	res.StatsTimestamp = time.Now()
+	return &pingResult{ProbeResult: res, source: req.Source, start: start}, nil
+}
 
-	return res, nil
+// probeRequest returns the probe request of the target, with the receiver-wide
+// defaults applied.
+func (s *pingScraper) probeRequest(target Target) ProbeRequest {
+	req := ProbeRequest{
+		Target:     target.Target,
+		Count:      s.defaultPingCount,
+		Timeout:    s.defaultPingTimeout,
+		PacketSize: s.defaultPacketSize,
+		TTL:        s.defaultTTL,
+		Interval:   s.defaultInterval,
+	}
+	if target.PingCount != nil {
+		req.Count = *target.PingCount
+	}
+	if target.PingTimeout != nil {
+		req.Timeout = *target.PingTimeout
+	}
+	if target.PacketSize != nil {
+		req.PacketSize = *target.PacketSize
+	}
+	if target.TTL != nil {
+		req.TTL = *target.TTL
+	}
+	if target.PingInterval != nil {
+		req.Interval = *target.PingInterval
+	}
+	req.Source = target.source(s.source)
+	req.Privileged = s.isPrivileged(target)
+	req.AddressFamily = target.addressFamily(s.addressFamily)
+	return req
 }
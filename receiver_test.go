@@ -2,14 +2,26 @@ package icmpreceiver
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/scraper/scrapererror"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
 	"go.uber.org/zap"
+
+	"github.com/supersun/otel-icmp-receiver/internal/metadata"
 )
 
 const (
@@ -29,19 +41,66 @@ var (
 			Logger: testLogger,
 		},
 	}
+
+	// testReplies are the answers of the fake prober used by the scrape tests.
+	testReplies = map[string]fakeReply{
+		"8.8.8.8": {
+			ip:   "8.8.8.8",
+			rtts: []time.Duration{10 * time.Millisecond, 12 * time.Millisecond, 14 * time.Millisecond, 16 * time.Millisecond},
+		},
+		"1.1.1.1": {
+			ip:   "1.1.1.1",
+			rtts: []time.Duration{5 * time.Millisecond, 7 * time.Millisecond},
+		},
+		"dualstack.example.com": {
+			ip:   "192.0.2.10",
+			ip6:  "2001:db8::10",
+			rtts: []time.Duration{20 * time.Millisecond},
+		},
+		"unreachable.example.com": {ip: "192.0.2.1"},
+		"blackhole.example.com":   {ip: "192.0.2.2", block: true},
+		"broken.example.com":      {ip: "192.0.2.3", err: errors.New("socket: permission denied")},
+	}
 )
 
+// metricByName returns the first metric with the given name.
+func metricByName(metrics pmetric.Metrics, name string) (pmetric.Metric, bool) {
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		scopeMetrics := metrics.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			ms := scopeMetrics.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				if ms.At(k).Name() == name {
+					return ms.At(k), true
+				}
+			}
+		}
+	}
+	return pmetric.Metric{}, false
+}
+
+// gaugeDataPoints returns the data points of the named gauge, which are empty
+// when the metric was not emitted.
+func gaugeDataPoints(metrics pmetric.Metrics, name string) pmetric.NumberDataPointSlice {
+	metric, ok := metricByName(metrics, name)
+	if !ok {
+		return pmetric.NewNumberDataPointSlice()
+	}
+	return metric.Gauge().DataPoints()
+}
+
 func TestSuccessfulPingScrape(t *testing.T) {
 	// Setup config
 	cfg := &Config{
-		ControllerConfig:   testControllerCfg,
-		Targets:            []Target{{Target: "8.8.8.8"}}, // Google's public DNS
-		DefaultPingCount:   4,
-		DefaultPingTimeout: defaultPingTimeout,
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}}, // Google's public DNS
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
 	}
 
 	// Create the scraper
-	pingScraper, err := newPingScraper(cfg, testSettings)
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
 	assert.NoError(t, err)
 
 	// Simulate a scrape
@@ -58,86 +117,356 @@ func TestSuccessfulPingScrape(t *testing.T) {
 	// Verify that the scrape contains the expected metrics
 	scopeMetrics := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
 	assert.Equal(
-		t, 6, scopeMetrics.Len(),
-	) // We expect 6 metrics: rtt, rtt.min, rtt.max, rtt.avg, rtt.stddev, loss.ratio
+		t, 14, scopeMetrics.Len(),
+	) // We expect 14 metrics: rtt, rtt.min, rtt.max, rtt.avg, rtt.stddev, rtt.p50, rtt.p90, rtt.p99, jitter, loss.ratio,
+	// reachable and packets.sent, packets.received, packets.duplicate
 
 	// Verify that one of the metrics has data
-	rttMetric := scopeMetrics.At(0)
-	assert.Equal(t, "ping.rtt", rttMetric.Name())
+	rttMetric, ok := metricByName(metrics, "ping.rtt")
+	require.True(t, ok)
 	assert.Equal(t, "ms", rttMetric.Unit())
 
-	// Check if data points exist before accessing them
+	// Verify that the data points for rtt contain the expected values
 	rttDataPoints := rttMetric.Gauge().DataPoints()
-	if rttDataPoints.Len() > 0 {
-		// Verify that the data points for rtt contain expected values
-		rttDataPoint := rttDataPoints.At(0)
-		assert.Greater(t, rttDataPoint.DoubleValue(), 0.0)
-	} else {
-		// If ping.rtt has no data points (e.g., ICMP blocked, network unavailable, or all packets timed out),
-		// check that stats metrics still have data points (they should be added even if no packets were received)
-		// The stats metrics should have at least 1 data point per target
-		lossRatioMetric := scopeMetrics.At(5) // loss.ratio is the 6th metric (index 5)
-		lossRatioDataPoints := lossRatioMetric.Gauge().DataPoints()
-		assert.Greater(
-			t, lossRatioDataPoints.Len(), 0,
-			"Stats metrics should have data points even if ping.rtt has no packets (ping was attempted)",
-		)
-		// Verify the stats metric has valid data
-		lossRatioDataPoint := lossRatioDataPoints.At(0)
-		assert.GreaterOrEqual(t, lossRatioDataPoint.DoubleValue(), 0.0, "Loss ratio should be >= 0")
-		t.Logf(
-			"ping.rtt has no data points (likely ICMP blocked, network issue, or all packets timed out), but stats metrics exist with %d data points",
-			lossRatioDataPoints.Len(),
-		)
+	require.Equal(t, 4, rttDataPoints.Len())
+	assert.InDelta(t, 10., rttDataPoints.At(0).DoubleValue(), 1e-9)
+	assert.InDelta(t, 16., rttDataPoints.At(3).DoubleValue(), 1e-9)
+
+	peerIP, _ := rttDataPoints.At(0).Attributes().Get(AttrPeerIp)
+	assert.Equal(t, "8.8.8.8", peerIP.Str())
+
+	// Verify the stats metrics: min, max, avg, stddev and loss ratio
+	expectedStats := map[string]float64{
+		"ping.rtt.min":    10,
+		"ping.rtt.max":    16,
+		"ping.rtt.avg":    13,
+		"ping.rtt.stddev": 2.23606797749979,
+		"ping.rtt.p50":    13,
+		"ping.rtt.p90":    15.4,
+		"ping.rtt.p99":    15.94,
+		"ping.jitter":     0.35205078125,
+		"ping.loss.ratio": 0,
+	}
+	for name, expected := range expectedStats {
+		dataPoints := gaugeDataPoints(metrics, name)
+		require.Equal(t, 1, dataPoints.Len(), name)
+		assert.InDelta(t, expected, dataPoints.At(0).DoubleValue(), 1e-5, name)
+	}
+
+	reachable := gaugeDataPoints(metrics, "ping.reachable")
+	require.Equal(t, 1, reachable.Len())
+	assert.Equal(t, int64(1), reachable.At(0).IntValue())
+}
+
+func TestPingScrapeWithDelayVariation(t *testing.T) {
+	metricsBuilderConfig := metadata.DefaultMetricsBuilderConfig()
+	metricsBuilderConfig.Metrics.PingIpdvMin.Enabled = true
+	metricsBuilderConfig.Metrics.PingIpdvMax.Enabled = true
+	metricsBuilderConfig.Metrics.PingIpdvAvg.Enabled = true
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metricsBuilderConfig,
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "dualstack.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	pingScraper.resolver = prober
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	// Round-trip times grow by 2 ms per packet. The target answering a single
+	// packet has no delay variation.
+	for name, expected := range map[string]float64{"ping.ipdv.min": 2, "ping.ipdv.max": 2, "ping.ipdv.avg": 2} {
+		dataPoints := gaugeDataPoints(metrics, name)
+		require.Equal(t, 1, dataPoints.Len(), name)
+		assert.InDelta(t, expected, dataPoints.At(0).DoubleValue(), 1e-9, name)
+		peerIP, _ := dataPoints.At(0).Attributes().Get(AttrPeerIp)
+		assert.Equal(t, "8.8.8.8", peerIP.Str(), name)
+	}
+	assert.Equal(t, 1, gaugeDataPoints(metrics, "ping.jitter").Len())
+}
+
+func TestPingScrapeWithTrimmedMean(t *testing.T) {
+	metricsBuilderConfig := metadata.DefaultMetricsBuilderConfig()
+	metricsBuilderConfig.Metrics.PingRttTrimmedMean.Enabled = true
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metricsBuilderConfig,
+		Targets:              []Target{{Target: "8.8.8.8"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		RttTrimRatio:         .25,
+	}
+
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	require.NoError(t, err)
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	// The fastest and the slowest of the 4 replies are dropped
+	dataPoints := gaugeDataPoints(metrics, "ping.rtt.trimmed_mean")
+	require.Equal(t, 1, dataPoints.Len())
+	assert.InDelta(t, 13., dataPoints.At(0).DoubleValue(), 1e-9)
+}
+
+func TestPingScrapeWithResourceAttributes(t *testing.T) {
+	metricsBuilderConfig := metadata.DefaultMetricsBuilderConfig()
+	metricsBuilderConfig.ResourceAttributes.HostName.Enabled = true
+	metricsBuilderConfig.ResourceAttributes.IcmpcheckReceiverName.Enabled = true
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metricsBuilderConfig,
+		Targets:              []Target{{Target: "8.8.8.8"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	settings := testSettings
+	settings.ID = component.NewIDWithName(metadata.Type, "5s")
+	pingScraper, err := newPingScraper(cfg, settings, newFakeProber(testReplies))
+	require.NoError(t, err)
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	assert.Equal(
+		t, map[string]any{"host.name": hostname, "icmpcheck.receiver.name": "icmpcheck/5s"},
+		metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw(),
+	)
+}
+
+func TestPingScrapeCountsPackets(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+
+	first, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	firstSent, ok := metricByName(first, "ping.packets.sent")
+	require.True(t, ok)
+	var starts []pcommon.Timestamp
+	for _, dp := range firstSent.Sum().DataPoints().All() {
+		require.NotZero(t, dp.StartTimestamp())
+		starts = append(starts, dp.StartTimestamp())
+	}
+
+	var metrics pmetric.Metrics
+	for range 2 {
+		metrics, err = pingScraper.Scrape(context.Background())
+		require.NoError(t, err)
+	}
+
+	// Counts add up across scrapes, per target and since it was first pinged
+	expected := map[string][]int64{
+		"ping.packets.sent":      {12, 12},
+		"ping.packets.received":  {12, 6},
+		"ping.packets.duplicate": {0, 0},
+	}
+	for name, counts := range expected {
+		metric, ok := metricByName(metrics, name)
+		require.True(t, ok, name)
+		assert.Equal(t, "{packet}", metric.Unit(), name)
+		assert.True(t, metric.Sum().IsMonotonic(), name)
+		assert.Equal(t, pmetric.AggregationTemporalityCumulative, metric.Sum().AggregationTemporality(), name)
+
+		dataPoints := metric.Sum().DataPoints()
+		require.Equal(t, len(counts), dataPoints.Len(), name)
+		for i, count := range counts {
+			dp := dataPoints.At(i)
+			assert.Equal(t, count, dp.IntValue(), name)
+			assert.Equal(t, starts[i], dp.StartTimestamp(), "%s starts when the target was first pinged", name)
+		}
 	}
 }
 
+func TestPingScrapeExpiresPacketCounts(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "rotating.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		SeriesExpiryScrapes:  2,
+	}
+
+	rtts := []time.Duration{10 * time.Millisecond, 12 * time.Millisecond, 14 * time.Millisecond, 16 * time.Millisecond}
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(map[string]fakeReply{
+		"192.0.2.30": {ip: "192.0.2.30", rtts: rtts},
+		"192.0.2.31": {ip: "192.0.2.31", rtts: rtts},
+	}))
+	require.NoError(t, err)
+	addrs := map[string][]string{"rotating.example.com": {"192.0.2.30"}}
+	pingScraper.resolver = fakeResolver{addrs: addrs}
+
+	// scrape returns the packets sent to each address since it was first pinged
+	scrape := func() map[string]int64 {
+		t.Helper()
+		metrics, _ := pingScraper.Scrape(context.Background())
+		sent := make(map[string]int64)
+		if metric, ok := metricByName(metrics, "ping.packets.sent"); ok {
+			for _, dp := range metric.Sum().DataPoints().All() {
+				peerIP, _ := dp.Attributes().Get(AttrPeerIp)
+				sent[peerIP.Str()] = dp.IntValue()
+			}
+		}
+		return sent
+	}
+	peerIPs := func() []string {
+		var ips []string
+		for key := range pingScraper.packetCounts {
+			ips = append(ips, key.peerIP)
+		}
+		return ips
+	}
+
+	assert.Equal(t, map[string]int64{"192.0.2.30": 4}, scrape())
+
+	// The counts of the previous address are kept for seriesExpiry scrapes
+	addrs["rotating.example.com"] = []string{"192.0.2.31"}
+	assert.Equal(t, map[string]int64{"192.0.2.31": 4}, scrape())
+	assert.ElementsMatch(t, []string{"192.0.2.30", "192.0.2.31"}, peerIPs())
+	assert.Equal(t, map[string]int64{"192.0.2.31": 8}, scrape())
+	assert.ElementsMatch(t, []string{"192.0.2.31"}, peerIPs())
+
+	// Scrapes that do not ping the target do not count
+	delete(addrs, "rotating.example.com")
+	for range 3 {
+		scrape()
+	}
+	assert.ElementsMatch(t, []string{"192.0.2.31"}, peerIPs())
+
+	// An address found again starts over
+	addrs["rotating.example.com"] = []string{"192.0.2.30"}
+	assert.Equal(t, map[string]int64{"192.0.2.30": 4}, scrape())
+}
+
 func TestPingScrapeWithDNSError(t *testing.T) {
 	// config with an invalid target (unresolvable DNS)
 	cfg := &Config{
-		ControllerConfig:   testControllerCfg,
-		Targets:            []Target{{Target: "invalid.target.com"}},
-		DefaultPingCount:   4,
-		DefaultPingTimeout: defaultPingTimeout,
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "invalid.target.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
 	}
 
-	pingScraper, err := newPingScraper(cfg, testSettings)
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
 	assert.NoError(t, err)
+	pingScraper.resolver = prober
 
 	metrics, err := pingScraper.Scrape(context.Background())
 
 	assert.NoError(t, err)    // No error should be returned, just a warning
-	assert.NotNil(t, metrics) // Metrics should still be returned, though they will be empty
+	assert.NotNil(t, metrics) // Metrics should still be returned
 
-	resourceMetrics := metrics.ResourceMetrics()
-	assert.NotNil(t, resourceMetrics)
-	assert.Equal(t, 1, resourceMetrics.Len()) // We expect exactly 1 ResourceMetrics (even if empty)
+	// Only the DNS metrics and ping.reachable have data points, the target is reported unreachable
+	assert.Equal(t, 3, metrics.DataPointCount(), "Only the DNS and reachability metrics should have data points")
+	assert.Equal(t, 1, gaugeDataPoints(metrics, "ping.dns.duration").Len())
 
-	scopeMetrics := resourceMetrics.At(0).ScopeMetrics().At(0).Metrics()
+	reachable := gaugeDataPoints(metrics, "ping.reachable")
+	require.Equal(t, 1, reachable.Len())
+	assert.Equal(t, int64(0), reachable.At(0).IntValue())
 	assert.Equal(
-		t, 6, scopeMetrics.Len(),
-	) // Expecting 6 metrics (rtt, rtt.min, rtt.max, rtt.avg, rtt.stddev, loss.ratio)
+		t, map[string]any{
+			AttrPeerIp:     "",
+			AttrPeerName:   "invalid.target.com",
+			AttrSockFamily: "inet",
+			AttrTag:        "",
+		}, reachable.At(0).Attributes().AsRaw(),
+	)
 
-	// Check that each of the metrics has no data points
-	for i := 0; i < scopeMetrics.Len(); i++ {
-		metric := scopeMetrics.At(i)
-		dataPoints := metric.Gauge().DataPoints()
-		// Ensure that the metric has no data points due to DNS failure
-		assert.Equal(t, 0, dataPoints.Len(), "No data points should exist for any metric due to DNS failure")
+	for range 2 {
+		metrics, err = pingScraper.Scrape(context.Background())
+		require.NoError(t, err)
 	}
+	dnsErrors, ok := metricByName(metrics, "ping.dns.errors")
+	require.True(t, ok)
+	assert.True(t, dnsErrors.Sum().IsMonotonic())
+	require.Equal(t, 1, dnsErrors.Sum().DataPoints().Len())
+
+	dp := dnsErrors.Sum().DataPoints().At(0)
+	assert.Equal(t, int64(3), dp.IntValue(), "Failed lookups are counted since start")
+	assert.Equal(
+		t, map[string]any{
+			AttrPeerName:      "invalid.target.com",
+			"dns.lookup.type": "ip",
+			"error.type":      "not_found",
+			AttrTag:           "",
+		}, dp.Attributes().AsRaw(),
+	)
+}
+
+func TestPingScrapeExpiresDNSErrors(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "_icmp._udp.example.com", ResolveMode: ResolveModeSRV}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		SeriesExpiryScrapes:  2,
+	}
+
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	require.NoError(t, err)
+	srvs := map[string][]string{"_icmp._udp.example.com": {"gone.example.com"}}
+	pingScraper.resolver = fakeResolver{addrs: map[string][]string{"backend.example.com": {"8.8.8.8"}}, srvs: srvs}
+
+	// errorNames returns the names of the ping.dns.errors series kept
+	errorNames := func() []string {
+		var names []string
+		for key := range pingScraper.dnsErrors {
+			names = append(names, key.name)
+		}
+		return names
+	}
+
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gone.example.com"}, errorNames())
+
+	// The errors of a host no longer in the SRV records are kept for
+	// seriesExpiry scrapes
+	srvs["_icmp._udp.example.com"] = []string{"backend.example.com"}
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gone.example.com"}, errorNames())
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, errorNames())
 }
 
 func TestPingScrapeWithTimeout(t *testing.T) {
-	// config with a very short ping timeout
+	// config with a target that never answers within the ping timeout
 	cfg := &Config{
-		ControllerConfig:   testControllerCfg,
-		Targets:            []Target{{Target: "8.8.8.8"}}, // Google's public DNS
-		DefaultPingCount:   4,
-		DefaultPingTimeout: 1 * time.Nanosecond, // Very short timeout
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "unreachable.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
 	}
 
-	pingScraper, err := newPingScraper(cfg, testSettings)
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
 	assert.NoError(t, err)
+	pingScraper.resolver = prober
 
 	metrics, err := pingScraper.Scrape(context.Background())
 
@@ -150,32 +479,40 @@ func TestPingScrapeWithTimeout(t *testing.T) {
 	assert.NotNil(t, resourceMetrics)
 	assert.Equal(t, 1, resourceMetrics.Len()) // Expecting 1 ResourceMetrics
 
-	// Check that we have exactly 6 metrics (rtt, rtt.min, rtt.max, rtt.avg, rtt.stddev, loss.ratio)
+	// Only the packet counts, reachability, loss ratio and DNS metrics are emitted: without any reply, the rtt
+	// metrics would be a misleading 0
 	scopeMetrics := resourceMetrics.At(0).ScopeMetrics().At(0).Metrics()
-	assert.Equal(t, 6, scopeMetrics.Len())
-
-	// Ensure that there are no data points for each metric (since ping timed out)
-	for i := 0; i < scopeMetrics.Len(); i++ {
-		metric := scopeMetrics.At(i)
-		dataPoints := metric.Gauge().DataPoints()
-		if metric.Name() != "ping.rtt" {
-			assert.Equal(t, 1, dataPoints.Len(), "Empty data points should exist due to the timeout error")
-		} else {
-			assert.Equal(t, 0, dataPoints.Len(), "No data points should exist for rtt metric due to ping timeout")
-		}
+	var names []string
+	for _, metric := range scopeMetrics.All() {
+		names = append(names, metric.Name())
 	}
+	assert.ElementsMatch(
+		t, []string{
+			"ping.dns.duration", "ping.packets.sent", "ping.packets.received", "ping.packets.duplicate", "ping.reachable",
+			"ping.loss.ratio",
+		}, names,
+	)
+	assert.Equal(t, 6, metrics.DataPointCount())
+
+	lossRatio := gaugeDataPoints(metrics, "ping.loss.ratio").At(0).DoubleValue()
+	assert.InDelta(t, 1., lossRatio, 1e-9)
+	reachable := gaugeDataPoints(metrics, "ping.reachable").At(0).IntValue()
+	assert.Equal(t, int64(0), reachable)
 }
 
 func TestPingScrapeWithMultipleTargets(t *testing.T) {
 	// Setup config with multiple valid targets
 	cfg := &Config{
-		ControllerConfig:   testControllerCfg,
-		Targets:            []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1"}}, // Google's and Cloudflare's public DNS
-		DefaultPingCount:   4,
-		DefaultPingTimeout: defaultPingTimeout,
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1"}}, // Google's and Cloudflare's public DNS
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		MaxConcurrency:       2,
 	}
 
-	pingScraper, err := newPingScraper(cfg, testSettings)
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
 	assert.NoError(t, err)
 
 	metrics, err := pingScraper.Scrape(context.Background())
@@ -183,6 +520,7 @@ func TestPingScrapeWithMultipleTargets(t *testing.T) {
 	// Assertions
 	assert.NoError(t, err)
 	assert.NotNil(t, metrics)
+	assert.ElementsMatch(t, []string{"8.8.8.8", "1.1.1.1"}, prober.probedTargets())
 
 	resourceMetrics := metrics.ResourceMetrics()
 	assert.NotNil(t, resourceMetrics)
@@ -191,31 +529,1179 @@ func TestPingScrapeWithMultipleTargets(t *testing.T) {
 	// Verify that the scrape contains the expected metrics
 	scopeMetrics := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
 	assert.Equal(
-		t, 6, scopeMetrics.Len(),
-	) // We expect 6 metrics: rtt, rtt.min, rtt.max, rtt.avg, rtt.stddev, loss.ratio
+		t, 14, scopeMetrics.Len(),
+	) // We expect 14 metrics: rtt, rtt.min, rtt.max, rtt.avg, rtt.stddev, rtt.p50, rtt.p90, rtt.p99, jitter, loss.ratio,
+	// reachable and packets.sent, packets.received, packets.duplicate
 
-	// Verify that there are data points for both targets (if ping succeeded)
-	// Note: If ICMP is blocked or network unavailable, there may be 0 data points
-	rttMetric := scopeMetrics.At(0)
-	rttDataPoints := rttMetric.Gauge().DataPoints()
-	if rttDataPoints.Len() > 0 {
-		// If we have data points, verify we have the expected number (2 targets = 2 data points if both succeed)
-		assert.GreaterOrEqual(t, rttDataPoints.Len(), 1, "Should have at least 1 data point if ping succeeded")
-		// Check that stats metrics have data points for both targets
-		lossRatioMetric := scopeMetrics.At(5)
-		assert.Equal(
-			t, 2, lossRatioMetric.Gauge().DataPoints().Len(), "Stats metrics should have data points for both targets",
+	// Verify that there are data points for both targets
+	rttDataPoints := gaugeDataPoints(metrics, "ping.rtt")
+	assert.Equal(t, 6, rttDataPoints.Len())
+
+	// Stats data points follow the order of the configured targets
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 2, lossRatioDataPoints.Len(), "Stats metrics should have data points for both targets")
+	for i, expected := range []string{"8.8.8.8", "1.1.1.1"} {
+		peerName, _ := lossRatioDataPoints.At(i).Attributes().Get(AttrPeerName)
+		assert.Equal(t, expected, peerName.Str())
+	}
+	assert.InDelta(t, 0., lossRatioDataPoints.At(0).DoubleValue(), 1e-9)
+	assert.InDelta(t, .5, lossRatioDataPoints.At(1).DoubleValue(), 1e-9)
+
+	// A target answering some of the echo requests is reachable
+	reachableDataPoints := gaugeDataPoints(metrics, "ping.reachable")
+	require.Equal(t, 2, reachableDataPoints.Len())
+	assert.Equal(t, int64(1), reachableDataPoints.At(1).IntValue())
+}
+
+func TestPingScrapeWithCanceledContext(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	metrics, err := pingScraper.Scrape(ctx)
+
+	// Targets cut off by the scrape context are reported as timed out
+	var partialErr scrapererror.PartialScrapeError
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 2, partialErr.Failed)
+	assert.ErrorContains(t, err, `target "8.8.8.8" timed out`)
+	assert.NotNil(t, metrics)
+	assert.Empty(t, prober.probedTargets())
+
+	// They were not measured, so they are not reported unreachable either
+	assert.Equal(t, 0, metrics.DataPointCount())
+}
+
+func TestPingScrapeWithDeadline(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "blackhole.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	assert.NoError(t, err)
+	pingScraper.resolver = prober
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	metrics, err := pingScraper.Scrape(ctx)
+
+	// The blocked target is reported as timed out, with what it got before the deadline
+	var partialErr scrapererror.PartialScrapeError
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Failed)
+	assert.ErrorContains(t, err, `target "blackhole.example.com" at 192.0.2.2 timed out after 1 packets sent, 0 received`)
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 2, lossRatioDataPoints.Len())
+	assert.InDelta(t, 0., lossRatioDataPoints.At(0).DoubleValue(), 1e-9)
+	assert.InDelta(t, 1., lossRatioDataPoints.At(1).DoubleValue(), 1e-9)
+}
+
+func TestPingScrapeWithTargetInterval(t *testing.T) {
+	interval := 10 * time.Millisecond
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1", Interval: &interval}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	require.NoError(t, pingScraper.start(context.Background(), nil))
+	defer func() { assert.NoError(t, pingScraper.shutdown(context.Background())) }()
+
+	// The target with an interval is pinged in the background, on its own cadence
+	require.Eventually(
+		t, func() bool { return slices.Index(prober.probedTargets(), "1.1.1.1") != -1 }, time.Second, time.Millisecond,
+	)
+	assert.NotContains(t, prober.probedTargets(), "8.8.8.8")
+	require.Eventually(
+		t, func() bool {
+			return len(slices.DeleteFunc(prober.probedTargets(), func(t string) bool { return t != "1.1.1.1" })) >= 3
+		}, time.Second, time.Millisecond,
+	)
+
+	// A scrape pings the other targets, and reports every run completed since the last scrape
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	peers := map[string]int{}
+	for _, dp := range gaugeDataPoints(metrics, "ping.loss.ratio").All() {
+		peerIP, _ := dp.Attributes().Get(AttrPeerIp)
+		peers[peerIP.Str()]++
+	}
+	assert.Equal(t, 1, peers["8.8.8.8"])
+	assert.GreaterOrEqual(t, peers["1.1.1.1"], 3)
+}
+
+func TestPingScrapeSharesMaxConcurrencyWithTargetIntervals(t *testing.T) {
+	interval := time.Millisecond
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "192.0.2.40"}, {Target: "192.0.2.41"},
+			{Target: "192.0.2.42", Interval: &interval}, {Target: "192.0.2.43", Interval: &interval},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		MaxConcurrency:     2,
+	}
+
+	replies := make(map[string]fakeReply)
+	for _, target := range cfg.Targets {
+		replies[target.Target] = fakeReply{ip: target.Target, delay: 5 * time.Millisecond}
+	}
+	prober := newFakeProber(replies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	require.NoError(t, pingScraper.start(context.Background(), nil))
+	defer func() { assert.NoError(t, pingScraper.shutdown(context.Background())) }()
+
+	// Targets with an interval take their slots from the same pool as the scrapes
+	for range 5 {
+		_, err := pingScraper.Scrape(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, prober.mostRunning())
+}
+
+func TestPingScrapeWithContinuousMode(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "unreachable.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   20 * time.Millisecond,
+		DefaultPingInterval:  time.Millisecond,
+		Mode:                 ModeContinuous,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	pingScraper.resolver = prober
+	require.NoError(t, pingScraper.start(context.Background(), nil))
+	defer func() { assert.NoError(t, pingScraper.shutdown(context.Background())) }()
+
+	// Probes run from start, a scrape reports the window since start
+	require.Eventually(
+		t, func() bool { return len(prober.probedTargets()) == 2 }, time.Second, time.Millisecond,
+	)
+	time.Sleep(50 * time.Millisecond)
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 2, lossRatioDataPoints.Len())
+	assert.InDelta(t, 0., lossRatioDataPoints.At(0).DoubleValue(), 1e-9)
+	assert.InDelta(t, 1., lossRatioDataPoints.At(1).DoubleValue(), 1e-9)
+
+	assert.Greater(t, gaugeDataPoints(metrics, "ping.rtt").Len(), 4, "Packets are sent at a steady rate")
+	minDataPoints := gaugeDataPoints(metrics, "ping.rtt.min")
+	require.Equal(t, 1, minDataPoints.Len())
+	assert.InDelta(t, 10., minDataPoints.At(0).DoubleValue(), 1e-9)
+	assert.Equal(t, 1, gaugeDataPoints(metrics, "ping.jitter").Len())
+	reachableDataPoints := gaugeDataPoints(metrics, "ping.reachable")
+	require.Equal(t, 2, reachableDataPoints.Len())
+	assert.Equal(t, int64(0), reachableDataPoints.At(1).IntValue())
+
+	// The next scrape reports the packets of its own window, from the same probes
+	sent, _ := metricByName(metrics, "ping.packets.sent")
+	firstSent := sent.Sum().DataPoints().At(0).IntValue()
+	firstRtts := gaugeDataPoints(metrics, "ping.rtt").Len()
+	time.Sleep(30 * time.Millisecond)
+	next, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	nextRtts := gaugeDataPoints(next, "ping.rtt").Len()
+	sent, _ = metricByName(next, "ping.packets.sent")
+	assert.Equal(t, int64(firstRtts+nextRtts), sent.Sum().DataPoints().At(0).IntValue())
+	assert.Greater(t, sent.Sum().DataPoints().At(0).IntValue(), firstSent)
+	assert.Len(t, prober.probedTargets(), 2)
+}
+
+func TestPingScrapeWithContinuousModeReportsFailedProbes(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "192.0.2.50"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   20 * time.Millisecond,
+		DefaultPingInterval:  time.Millisecond,
+		Mode:                 ModeContinuous,
+	}
+
+	prober := newFakeProber(map[string]fakeReply{
+		"192.0.2.50": {
+			ip: "192.0.2.50", rtts: []time.Duration{10 * time.Millisecond}, err: errors.New("sendto: network is unreachable"),
+			failAfter: 3,
+		},
+	})
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	require.NoError(t, pingScraper.start(context.Background(), nil))
+	defer func() { assert.NoError(t, pingScraper.shutdown(context.Background())) }()
+
+	// The probe fails after its third request
+	time.Sleep(50 * time.Millisecond)
+	metrics, err := pingScraper.Scrape(context.Background())
+
+	// The failure is reported along with the packets exchanged until then
+	assert.ErrorContains(t, err, `failed to execute pinger for target "192.0.2.50": sendto: network is unreachable`)
+	sent, ok := metricByName(metrics, "ping.packets.sent")
+	require.True(t, ok)
+	assert.Equal(t, int64(3), sent.Sum().DataPoints().At(0).IntValue())
+	reachable := gaugeDataPoints(metrics, "ping.reachable")
+	require.Equal(t, 1, reachable.Len())
+	assert.Equal(t, int64(1), reachable.At(0).IntValue())
+}
+
+func TestPingScrapeWithContinuousModeKeepsRotatedAddress(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "rotating.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   20 * time.Millisecond,
+		DefaultPingInterval:  time.Millisecond,
+		Mode:                 ModeContinuous,
+	}
+
+	prober := newFakeProber(map[string]fakeReply{
+		"first.example.com":  {ip: "192.0.2.1", rtts: []time.Duration{10 * time.Millisecond}},
+		"second.example.com": {ip: "192.0.2.2", rtts: []time.Duration{10 * time.Millisecond}},
+	})
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	resolver := fakeResolver{addrs: map[string][]string{"rotating.example.com": {"192.0.2.1", "192.0.2.2"}}}
+	pingScraper.resolver = resolver
+	require.NoError(t, pingScraper.start(context.Background(), nil))
+	defer func() { assert.NoError(t, pingScraper.shutdown(context.Background())) }()
+
+	peerIPs := func(metrics pmetric.Metrics) []string {
+		var ips []string
+		for _, dp := range gaugeDataPoints(metrics, "ping.reachable").All() {
+			peerIP, _ := dp.Attributes().Get(AttrPeerIp)
+			ips = append(ips, peerIP.Str())
+		}
+		return ips
+	}
+
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Eventually(
+		t, func() bool { return len(prober.probedTargets()) == 1 }, time.Second, time.Millisecond,
+	)
+	time.Sleep(30 * time.Millisecond)
+
+	// The probe is kept while its address is still among the answers
+	resolver.addrs["rotating.example.com"] = []string{"192.0.2.2", "192.0.2.1"}
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1"}, peerIPs(metrics))
+	assert.Equal(t, []string{"192.0.2.1"}, prober.probedTargets())
+
+	// and replaced once its address is gone
+	resolver.addrs["rotating.example.com"] = []string{"192.0.2.2"}
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Eventually(
+		t, func() bool { return len(prober.probedTargets()) == 2 }, time.Second, time.Millisecond,
+	)
+	time.Sleep(30 * time.Millisecond)
+	metrics, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.2"}, peerIPs(metrics))
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, prober.probedTargets())
+}
+
+func TestPingScrapeWithSpread(t *testing.T) {
+	const seed = 7
+	spread := 50 * time.Millisecond
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1", Spread: &spread}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		RttMode:              RttModeHistogram,
+		Spread:               time.Millisecond,
+		Seed:                 seed,
+	}
+
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	require.NoError(t, err)
+
+	scrapeStart := time.Now()
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	// Offsets are drawn from the seed in the order of the targets
+	sampler := newSampler(seed)
+	offsets := []time.Duration{sampler.offset(time.Millisecond), sampler.offset(spread)}
+	rttMetric, ok := metricByName(metrics, "ping.rtt")
+	require.True(t, ok)
+	dataPoints := rttMetric.Histogram().DataPoints()
+	require.Equal(t, 2, dataPoints.Len())
+	for i, offset := range offsets {
+		start := dataPoints.At(i).StartTimestamp().AsTime()
+		assert.False(t, start.Before(scrapeStart.Add(offset)), "Pings start after their offset")
+		assert.Less(t, start.Sub(scrapeStart), time.Second)
+	}
+	assert.Greater(t, offsets[1], time.Millisecond)
+}
+
+func TestPingScrapeWithSpreadWaitsOutsideOfSlots(t *testing.T) {
+	const seed = 18
+	spread := 100 * time.Millisecond
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "192.0.2.40"}, {Target: "192.0.2.41"}, {Target: "192.0.2.42"}, {Target: "192.0.2.43"},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		Spread:             spread,
+		Seed:               seed,
+		MaxConcurrency:     1,
+	}
+
+	replies := make(map[string]fakeReply)
+	for _, target := range cfg.Targets {
+		replies[target.Target] = fakeReply{ip: target.Target, rtts: []time.Duration{10 * time.Millisecond}}
+	}
+	prober := newFakeProber(replies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+
+	// Offsets add up to more than twice spread, a target waiting for its
+	// start must not hold the only slot meanwhile
+	sampler := newSampler(seed)
+	var total time.Duration
+	for range cfg.Targets {
+		total += sampler.offset(spread)
+	}
+	require.Greater(t, total, 2*spread)
+
+	scrapeStart := time.Now()
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Less(t, time.Since(scrapeStart), spread+50*time.Millisecond)
+	assert.Len(t, prober.probedTargets(), 4)
+	assert.Equal(t, 1, prober.mostRunning())
+}
+
+func TestPingScrapeEmitsStateChanges(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "192.0.2.20", Attributes: map[string]string{"site": "ams1"}},
+			{Target: "nosuch.example.com"},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		StateChanges:       StateChangesConfig{LossThreshold: .5},
+	}
+
+	rtts := []time.Duration{10 * time.Millisecond, 12 * time.Millisecond, 14 * time.Millisecond, 16 * time.Millisecond}
+	replies := map[string]fakeReply{"192.0.2.20": {ip: "192.0.2.20", rtts: rtts}}
+	prober := newFakeProber(replies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	pingScraper.resolver = prober
+	sink := &consumertest.LogsSink{}
+	pingScraper.logs = sink
+
+	scrapeWith := func(rtts []time.Duration) plog.LogRecordSlice {
+		t.Helper()
+		replies["192.0.2.20"] = fakeReply{ip: "192.0.2.20", rtts: rtts}
+		sink.Reset()
+		_, err := pingScraper.Scrape(context.Background())
+		require.NoError(t, err)
+		if sink.LogRecordCount() == 0 {
+			return plog.NewLogRecordSlice()
+		}
+		return sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	}
+	attr := func(record plog.LogRecord, key string) any {
+		value, ok := record.Attributes().Get(key)
+		if !ok {
+			return nil
+		}
+		return value.AsRaw()
+	}
+
+	// Targets found up are not reported, unresolved ones are down
+	records := scrapeWith(rtts)
+	require.Equal(t, 1, records.Len())
+	assert.Equal(t, plog.SeverityNumberError, records.At(0).SeverityNumber())
+	assert.Equal(t, "nosuch.example.com", attr(records.At(0), AttrPeerName))
+	assert.Equal(t, stateUnknown, attr(records.At(0), AttrStatePrevious))
+	assert.Equal(t, StateDown, attr(records.At(0), AttrState))
+
+	records = scrapeWith(nil)
+	require.Equal(t, 1, records.Len())
+	down := records.At(0)
+	assert.Equal(t, "ping.state_change", down.EventName())
+	assert.Equal(t, "ERROR", down.SeverityText())
+	assert.Equal(t, `target "192.0.2.20" is down, was up`, down.Body().Str())
+	assert.Equal(t, StateUp, attr(down, AttrStatePrevious))
+	assert.Equal(t, StateDown, attr(down, AttrState))
+	assert.Equal(t, "192.0.2.20", attr(down, AttrPeerIp))
+	assert.Equal(t, "ams1", attr(down, "site"))
+	assert.Nil(t, attr(down, AttrOutageDuration))
+
+	records = scrapeWith(rtts[:2])
+	require.Equal(t, 1, records.Len())
+	assert.Equal(t, plog.SeverityNumberWarn, records.At(0).SeverityNumber())
+	assert.Equal(t, StateDegraded, attr(records.At(0), AttrState))
+	assert.Equal(t, .5, attr(records.At(0), AttrLossRatio))
+
+	records = scrapeWith(rtts)
+	require.Equal(t, 1, records.Len())
+	up := records.At(0)
+	assert.Equal(t, plog.SeverityNumberInfo, up.SeverityNumber())
+	assert.Equal(t, StateDegraded, attr(up, AttrStatePrevious))
+	assert.Equal(t, StateUp, attr(up, AttrState))
+	// Fake probes all complete at the same time, the outage lasts 0ms
+	assert.Equal(t, 0., attr(up, AttrOutageDuration))
+
+	assert.Zero(t, scrapeWith(rtts).Len(), "Unchanged states are not reported")
+}
+
+func TestPingScrapeIgnoresStateOfTimedOutTargets(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "192.0.2.20"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	rtts := []time.Duration{10 * time.Millisecond, 12 * time.Millisecond, 14 * time.Millisecond, 16 * time.Millisecond}
+	replies := map[string]fakeReply{"192.0.2.20": {ip: "192.0.2.20", rtts: rtts}}
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(replies))
+	require.NoError(t, err)
+	sink := &consumertest.LogsSink{}
+	pingScraper.logs = sink
+
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, sink.LogRecordCount())
+
+	// A target cut off before it was pinged
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pingScraper.Scrape(ctx)
+	require.Error(t, err)
+	assert.Zero(t, sink.LogRecordCount(), "A canceled scrape does not change the state")
+
+	// A target stopped while being pinged
+	replies["192.0.2.20"] = fakeReply{ip: "192.0.2.20", block: true}
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = pingScraper.Scrape(ctx)
+	require.Error(t, err)
+	assert.Zero(t, sink.LogRecordCount(), "A timed out probe does not change the state")
+
+	// The state is still the one of the last complete probe
+	replies["192.0.2.20"] = fakeReply{ip: "192.0.2.20"}
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, sink.LogRecordCount())
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	previous, _ := record.Attributes().Get(AttrStatePrevious)
+	assert.Equal(t, StateUp, previous.Str())
+}
+
+func TestPingScrapeReportsRecoveryFromDNSFailure(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "flaky.example.com"},
+			{Target: "pool.example.com", ResolveMode: ResolveModeAll},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+	}
+
+	rtts := []time.Duration{10 * time.Millisecond, 12 * time.Millisecond, 14 * time.Millisecond, 16 * time.Millisecond}
+	prober := newFakeProber(map[string]fakeReply{
+		"192.0.2.30": {ip: "192.0.2.30", rtts: rtts},
+		"192.0.2.31": {ip: "192.0.2.31", rtts: rtts},
+		"192.0.2.32": {ip: "192.0.2.32", rtts: rtts},
+	})
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	addrs := map[string][]string{
+		"flaky.example.com": {"192.0.2.30"},
+		"pool.example.com":  {"192.0.2.31", "192.0.2.32"},
+	}
+	pingScraper.resolver = fakeResolver{addrs: addrs}
+	sink := &consumertest.LogsSink{}
+	pingScraper.logs = sink
+
+	// scrape returns the peer name, IP address and state of the changes found
+	scrape := func() []string {
+		t.Helper()
+		sink.Reset()
+		_, err := pingScraper.Scrape(context.Background())
+		require.NoError(t, err)
+
+		var changes []string
+		for _, logs := range sink.AllLogs() {
+			for _, record := range logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().All() {
+				peerName, _ := record.Attributes().Get(AttrPeerName)
+				peerIP, _ := record.Attributes().Get(AttrPeerIp)
+				state, _ := record.Attributes().Get(AttrState)
+				changes = append(changes, peerName.Str()+" "+peerIP.Str()+" "+state.Str())
+			}
+		}
+		return changes
+	}
+
+	assert.Empty(t, scrape())
+
+	// Host names that cannot be resolved are down
+	delete(addrs, "flaky.example.com")
+	delete(addrs, "pool.example.com")
+	assert.Equal(t, []string{"flaky.example.com  down", "pool.example.com  down"}, scrape())
+
+	// and up again once they resolve, an address of the host name taking over
+	// its state
+	addrs["flaky.example.com"] = []string{"192.0.2.30"}
+	addrs["pool.example.com"] = []string{"192.0.2.31", "192.0.2.32"}
+	assert.Equal(t, []string{"flaky.example.com 192.0.2.30 up", "pool.example.com 192.0.2.31 up"}, scrape())
+	assert.Empty(t, scrape())
+}
+
+func TestPingScrapeAfterShutdown(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	assert.NoError(t, err)
+	assert.NoError(t, pingScraper.shutdown(context.Background()))
+
+	metrics, err := pingScraper.Scrape(context.Background())
+
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Equal(t, 0, gaugeDataPoints(metrics, "ping.loss.ratio").Len())
+}
+
+func TestPingScrapeWithProberError(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "broken.example.com"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	assert.NoError(t, err)
+	pingScraper.resolver = prober
+	sink := &consumertest.LogsSink{}
+	pingScraper.logs = sink
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	assert.ErrorContains(t, err, "socket: permission denied")
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+
+	// The target is reported unreachable, and down
+	reachableDataPoints := gaugeDataPoints(metrics, "ping.reachable")
+	require.Equal(t, 1, reachableDataPoints.Len())
+	assert.Equal(t, int64(0), reachableDataPoints.At(0).IntValue())
+	peerIP, _ := reachableDataPoints.At(0).Attributes().Get(AttrPeerIp)
+	assert.Equal(t, "192.0.2.3", peerIP.Str())
+	peerName, _ := reachableDataPoints.At(0).Attributes().Get(AttrPeerName)
+	assert.Equal(t, "broken.example.com", peerName.Str())
+
+	require.Equal(t, 1, sink.LogRecordCount())
+	state, _ := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get(AttrState)
+	assert.Equal(t, StateDown, state.Str())
+}
+
+func TestPingScrapeWithPartialErrors(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "broken.example.com"},
+			{Target: "8.8.8.8"},
+			{Target: "invalid.target.com"},
+			{Target: "broken.example.com"},
+			{Target: "1.1.1.1"},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	assert.NoError(t, err)
+	pingScraper.resolver = prober
+
+	metrics, err := pingScraper.Scrape(context.Background())
+
+	// Both broken targets are counted, the DNS failure is only logged
+	var partialErr scrapererror.PartialScrapeError
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 2, partialErr.Failed)
+	assert.ErrorContains(t, err, `failed to execute pinger for target "broken.example.com"`)
+
+	// Healthy targets are still reported
+	assert.Equal(t, 6, gaugeDataPoints(metrics, "ping.rtt").Len())
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 2, lossRatioDataPoints.Len())
+	for i, expected := range []string{"8.8.8.8", "1.1.1.1"} {
+		peerName, _ := lossRatioDataPoints.At(i).Attributes().Get(AttrPeerName)
+		assert.Equal(t, expected, peerName.Str())
+	}
+}
+
+func TestStartChecksSocketMode(t *testing.T) {
+	errDenied := errors.New("socket: operation not permitted")
+
+	tests := []struct {
+		name       string
+		privileged bool
+		socketErrs map[bool]error
+		wantErr    string
+	}{
+		{
+			name: "unprivileged works",
+		},
+		{
+			name:       "privileged works",
+			privileged: true,
+			socketErrs: map[bool]error{false: errDenied},
+		},
+		{
+			name:       "unprivileged denied",
+			socketErrs: map[bool]error{false: errDenied},
+			wantErr:    `set "privileged: true" to use raw sockets, which work here`,
+		},
+		{
+			name:       "privileged denied",
+			privileged: true,
+			socketErrs: map[bool]error{true: errDenied},
+			wantErr:    `set "privileged: false" to use unprivileged sockets, which work here`,
+		},
+		{
+			name:       "both denied",
+			socketErrs: map[bool]error{false: errDenied, true: errDenied},
+			wantErr:    "no ip4 ICMP socket mode works on this host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				cfg := &Config{
+					ControllerConfig:     testControllerCfg,
+					MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+					Targets:              []Target{{Target: "8.8.8.8"}},
+					DefaultPingCount:     4,
+					DefaultPingTimeout:   defaultPingTimeout,
+					Privileged:           tt.privileged,
+				}
+
+				prober := newFakeProber(testReplies)
+				prober.socketErrs = tt.socketErrs
+
+				pingScraper, err := newPingScraper(cfg, testSettings, prober)
+				require.NoError(t, err)
+
+				err = pingScraper.start(context.Background(), nil)
+				if tt.wantErr == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorContains(t, err, tt.wantErr)
+				}
+			},
 		)
-	} else {
-		// If ping.rtt has no data points, stats metrics should still have data
-		lossRatioMetric := scopeMetrics.At(5)
-		assert.Greater(
-			t, lossRatioMetric.Gauge().DataPoints().Len(), 0,
-			"Stats metrics should have data points even if ping.rtt has none",
+	}
+}
+
+func TestPingUsesTargetSocketMode(t *testing.T) {
+	privileged := true
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1", Privileged: &privileged}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	for _, req := range prober.requests {
+		assert.Equal(t, req.Target == "1.1.1.1", req.Privileged, req.Target)
+	}
+}
+
+func TestPingScrapeWithAddressFamilies(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "dualstack.example.com"},
+			{Target: "dualstack.example.com", AddressFamily: AddressFamilyIPv6},
+			{Target: "8.8.8.8", AddressFamily: AddressFamilyIPv6},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		AddressFamily:      AddressFamilyIPv4,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	pingScraper.resolver = prober
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err) // 8.8.8.8 has no IPv6 address, which is a DNS error
+
+	for _, metric := range []string{"ping.rtt", "ping.loss.ratio"} {
+		dataPoints := gaugeDataPoints(metrics, metric)
+		require.Equal(t, 2, dataPoints.Len(), metric)
+
+		for i, expected := range []struct{ ip, family string }{{"192.0.2.10", "inet"}, {"2001:db8::10", "inet6"}} {
+			attrs := dataPoints.At(i).Attributes()
+			peerName, _ := attrs.Get(AttrPeerName)
+			peerIP, _ := attrs.Get(AttrPeerIp)
+			family, _ := attrs.Get(AttrSockFamily)
+			assert.Equal(t, "dualstack.example.com", peerName.Str())
+			assert.Equal(t, expected.ip, peerIP.Str())
+			assert.Equal(t, expected.family, family.Str())
+		}
+	}
+}
+
+func TestPingUsesTargetPacketOptions(t *testing.T) {
+	packetSize, ttl, interval := 1472, 4, 100*time.Millisecond
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "8.8.8.8"},
+			{Target: "1.1.1.1", PacketSize: &packetSize, TTL: &ttl, PingInterval: &interval},
+		},
+		DefaultPingCount:    4,
+		DefaultPingTimeout:  defaultPingTimeout,
+		DefaultPacketSize:   56,
+		DefaultTTL:          64,
+		DefaultPingInterval: 200 * time.Millisecond,
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+
+	_, err = pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, prober.requests, 2)
+	for _, req := range prober.requests {
+		if req.Target == "1.1.1.1" {
+			assert.Equal(t, []any{1472, 4, 100 * time.Millisecond}, []any{req.PacketSize, req.TTL, req.Interval})
+		} else {
+			assert.Equal(t, []any{56, 64, 200 * time.Millisecond}, []any{req.PacketSize, req.TTL, req.Interval})
+		}
+	}
+}
+
+func TestPingScrapeWithSource(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "8.8.8.8"},
+			{Target: "1.1.1.1", Source: "192.0.2.100"},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		Source:             "eth1",
+	}
+
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	for _, req := range prober.requests {
+		if req.Target == "1.1.1.1" {
+			assert.Equal(t, "192.0.2.100", req.Source)
+		} else {
+			assert.Equal(t, "eth1", req.Source)
+		}
+	}
+
+	for _, dataPoints := range []pmetric.NumberDataPointSlice{
+		gaugeDataPoints(metrics, "ping.rtt"),
+		gaugeDataPoints(metrics, "ping.loss.ratio"),
+	} {
+		for i := 0; i < dataPoints.Len(); i++ {
+			attrs := dataPoints.At(i).Attributes()
+			peerName, _ := attrs.Get(AttrPeerName)
+			hostIP, hasHostIP := attrs.Get(AttrHostIp)
+			hostInterface, hasHostInterface := attrs.Get(AttrHostInterface)
+
+			if peerName.Str() == "1.1.1.1" {
+				assert.Equal(t, "192.0.2.100", hostIP.Str())
+				assert.False(t, hasHostInterface)
+			} else {
+				assert.Equal(t, "eth1", hostInterface.Str())
+				assert.False(t, hasHostIP)
+			}
+		}
+	}
+}
+
+func TestPingScrapeWithRttHistogram(t *testing.T) {
+	tests := []struct {
+		mode     string
+		wantType pmetric.MetricType
+	}{
+		{mode: RttModeHistogram, wantType: pmetric.MetricTypeHistogram},
+		{mode: RttModeExponentialHistogram, wantType: pmetric.MetricTypeExponentialHistogram},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.mode, func(t *testing.T) {
+				cfg := &Config{
+					ControllerConfig:     testControllerCfg,
+					MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+					Targets:              []Target{{Target: "8.8.8.8"}, {Target: "1.1.1.1"}},
+					DefaultPingCount:     4,
+					DefaultPingTimeout:   defaultPingTimeout,
+					RttMode:              tt.mode,
+					RttHistogramBuckets:  []float64{6, 11, 15},
+				}
+
+				pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+				require.NoError(t, err)
+
+				metrics, err := pingScraper.Scrape(context.Background())
+				require.NoError(t, err)
+
+				// ping.rtt is a single histogram instead of per-packet gauges
+				assert.Equal(t, 14, metrics.MetricCount())
+				rttMetric, ok := metricByName(metrics, "ping.rtt")
+				require.True(t, ok)
+				require.Equal(t, tt.wantType, rttMetric.Type())
+				assert.Equal(t, "ms", rttMetric.Unit())
+
+				if tt.mode == RttModeHistogram {
+					dataPoints := rttMetric.Histogram().DataPoints()
+					require.Equal(t, 2, dataPoints.Len())
+					assert.Equal(t, []uint64{0, 1, 2, 1}, dataPoints.At(0).BucketCounts().AsRaw())
+					assert.Equal(t, []uint64{1, 1, 0, 0}, dataPoints.At(1).BucketCounts().AsRaw())
+					assert.InDelta(t, 52., dataPoints.At(0).Sum(), 1e-9)
+
+					peerName, _ := dataPoints.At(1).Attributes().Get(AttrPeerName)
+					assert.Equal(t, "1.1.1.1", peerName.Str())
+					return
+				}
+
+				dataPoints := rttMetric.ExponentialHistogram().DataPoints()
+				require.Equal(t, 2, dataPoints.Len())
+				assert.Equal(t, uint64(4), dataPoints.At(0).Count())
+				assert.Equal(t, uint64(2), dataPoints.At(1).Count())
+				assert.InDelta(t, 10., dataPoints.At(0).Min(), 1e-9)
+				assert.InDelta(t, 16., dataPoints.At(0).Max(), 1e-9)
+			},
 		)
-		t.Logf(
-			"ping.rtt has no data points, but stats metrics have %d data points",
-			lossRatioMetric.Gauge().DataPoints().Len(),
+	}
+}
+
+func TestPingScrapeWithRttHistogramDisabled(t *testing.T) {
+	mbc := metadata.DefaultMetricsBuilderConfig()
+	mbc.Metrics.PingRtt.Enabled = false
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: mbc,
+		Targets:              []Target{{Target: "8.8.8.8"}},
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		RttMode:              RttModeHistogram,
+	}
+
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	require.NoError(t, err)
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	_, ok := metricByName(metrics, "ping.rtt")
+	assert.False(t, ok)
+	assert.Equal(t, 13, metrics.MetricCount())
+}
+
+func TestPingScrapeWithTargetAttributes(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "8.8.8.8", Tag: "dns", Attributes: map[string]string{"site": "ams1", "sla.tier": "gold"}},
+			{Target: "1.1.1.1"},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		Tag:                "probes",
+		RttMode:            RttModeHistogram,
+	}
+
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	require.NoError(t, err)
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+
+	// Every metric is reported once, with the data points of both targets
+	assert.Equal(t, 14, metrics.MetricCount())
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 2, lossRatioDataPoints.Len())
+
+	rttMetric, ok := metricByName(metrics, "ping.rtt")
+	require.True(t, ok)
+	rttDataPoints := rttMetric.Histogram().DataPoints()
+	require.Equal(t, 2, rttDataPoints.Len())
+
+	for _, attrs := range []pcommon.Map{lossRatioDataPoints.At(0).Attributes(), rttDataPoints.At(0).Attributes()} {
+		assert.Equal(
+			t, map[string]any{
+				AttrPeerIp:     "8.8.8.8",
+				AttrPeerName:   "8.8.8.8",
+				AttrSockFamily: "inet",
+				AttrTag:        "dns",
+				"site":         "ams1",
+				"sla.tier":     "gold",
+			}, attrs.AsRaw(),
 		)
 	}
+	for _, attrs := range []pcommon.Map{lossRatioDataPoints.At(1).Attributes(), rttDataPoints.At(1).Attributes()} {
+		tag, _ := attrs.Get(AttrTag)
+		assert.Equal(t, "probes", tag.Str())
+		_, hasSite := attrs.Get("site")
+		assert.False(t, hasSite)
+	}
+}
+
+func TestPingScrapeWithExpandedTargets(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "8.8.8.8"},
+			{Target: "192.0.2.0/29", Exclude: []string{"192.0.2.3-192.0.2.6"}},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+	}
+
+	prober := newFakeProber(
+		map[string]fakeReply{
+			"8.8.8.8":   testReplies["8.8.8.8"],
+			"192.0.2.1": {ip: "192.0.2.1", rtts: []time.Duration{time.Millisecond}},
+			"192.0.2.2": {ip: "192.0.2.2"},
+		},
+	)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"8.8.8.8", "192.0.2.1", "192.0.2.2"}, prober.probedTargets())
+
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 3, lossRatioDataPoints.Len())
+	for i, expected := range []struct{ peer, prefix string }{
+		{"8.8.8.8", ""},
+		{"192.0.2.1", "192.0.2.0/29"},
+		{"192.0.2.2", "192.0.2.0/29"},
+	} {
+		attrs := lossRatioDataPoints.At(i).Attributes()
+		peerName, _ := attrs.Get(AttrPeerName)
+		assert.Equal(t, expected.peer, peerName.Str())
+
+		prefix, ok := attrs.Get(AttrPeerPrefix)
+		assert.Equal(t, expected.prefix != "", ok)
+		assert.Equal(t, expected.prefix, prefix.Str())
+	}
+}
+
+func TestPingScrapeReloadsTargetsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	modTime := time.Now()
+	writeTargetsFile := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		// Make sure the change is seen on file systems with coarse timestamps.
+		modTime = modTime.Add(time.Second)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	writeTargetsFile(`[{"targets": ["1.1.1.1", "missing.example.com"], "labels": {"site": "paris"}}]`)
+
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets:              []Target{{Target: "8.8.8.8"}},
+		DefaultPingCount:     4,
+		// Targets read from the file are validated with the whole config.
+		DefaultPingTimeout: 5 * time.Second,
+		AddressFamily:      AddressFamilyAny,
+		TargetsFile:        path,
+	}
+	prober := newFakeProber(testReplies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	pingScraper.resolver = prober
+	pingScraper.logs = &consumertest.LogsSink{}
+	require.NoError(t, pingScraper.start(context.Background(), nil))
+
+	scrapedPeers := func() map[string]string {
+		metrics, err := pingScraper.Scrape(context.Background())
+		require.NoError(t, err)
+
+		peers := make(map[string]string)
+		for _, dp := range gaugeDataPoints(metrics, "ping.loss.ratio").All() {
+			peerName, _ := dp.Attributes().Get(AttrPeerName)
+			site, _ := dp.Attributes().Get("site")
+			peers[peerName.Str()] = site.Str()
+		}
+		return peers
+	}
+	assert.Equal(t, map[string]string{"8.8.8.8": "", "1.1.1.1": "paris"}, scrapedPeers())
+	assert.Len(t, pingScraper.dnsErrors, 1)
+	assert.Len(t, pingScraper.states.states, 3)
+
+	writeTargetsFile(`[{"targets": ["dualstack.example.com"], "labels": {"site": "lyon"}}]`)
+	assert.Equal(t, map[string]string{"8.8.8.8": "", "dualstack.example.com": "lyon"}, scrapedPeers())
+	// Counts of removed targets are forgotten.
+	assert.Empty(t, pingScraper.dnsErrors)
+	assert.Len(t, pingScraper.packetCounts, 2)
+	for key := range pingScraper.packetCounts {
+		assert.NotEqual(t, "1.1.1.1", key.peerIP)
+	}
+	assert.Len(t, pingScraper.states.states, 2)
+
+	// Invalid files are rejected, the last good targets are kept
+	for _, content := range []string{
+		`[{"targets": ["dualstack.example.com"]`,
+		`[{"targets": ["https://example.com"]}]`,
+		`[{"targets": ["8.8.8.8"]}]`,
+	} {
+		writeTargetsFile(content)
+		assert.Equal(t, map[string]string{"8.8.8.8": "", "dualstack.example.com": "lyon"}, scrapedPeers())
+	}
+
+	require.NoError(t, os.Remove(path))
+	assert.Equal(t, map[string]string{"8.8.8.8": "", "dualstack.example.com": "lyon"}, scrapedPeers())
+}
+
+func TestStartFailsWithInvalidTargetsFile(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		DefaultPingCount:     4,
+		DefaultPingTimeout:   defaultPingTimeout,
+		AddressFamily:        AddressFamilyAny,
+		TargetsFile:          filepath.Join(t.TempDir(), "missing.yaml"),
+	}
+	pingScraper, err := newPingScraper(cfg, testSettings, newFakeProber(testReplies))
+	require.NoError(t, err)
+	assert.ErrorContains(t, pingScraper.start(context.Background(), nil), "cannot load targets file")
+}
+
+func TestPingScrapeWithResolveModes(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     testControllerCfg,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Targets: []Target{
+			{Target: "8.8.8.8"},
+			{Target: "api.example.com", ResolveMode: ResolveModeAll, AddressFamily: AddressFamilyIPv4},
+			{Target: "_icmp._udp.example.com", ResolveMode: ResolveModeSRV},
+		},
+		DefaultPingCount:   4,
+		DefaultPingTimeout: defaultPingTimeout,
+		AddressFamily:      AddressFamilyAny,
+	}
+
+	replies := map[string]fakeReply{"8.8.8.8": testReplies["8.8.8.8"]}
+	for _, ip := range []string{"192.0.2.10", "192.0.2.11", "192.0.2.21", "192.0.2.22"} {
+		replies[ip] = fakeReply{ip: ip, rtts: []time.Duration{time.Millisecond}}
+	}
+	prober := newFakeProber(replies)
+	pingScraper, err := newPingScraper(cfg, testSettings, prober)
+	require.NoError(t, err)
+	pingScraper.resolver = testResolver
+
+	metrics, err := pingScraper.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(
+		t, []string{"8.8.8.8", "192.0.2.10", "192.0.2.11", "192.0.2.21", "192.0.2.22"}, prober.probedTargets(),
+	)
+
+	// Every address has its own series, named after the host it resolved from
+	lossRatioDataPoints := gaugeDataPoints(metrics, "ping.loss.ratio")
+	require.Equal(t, 5, lossRatioDataPoints.Len())
+	for i, expected := range []struct{ ip, name string }{
+		{"8.8.8.8", "8.8.8.8"},
+		{"192.0.2.10", "api.example.com"},
+		{"192.0.2.11", "api.example.com"},
+		{"192.0.2.21", "backend1.example.com"},
+		{"192.0.2.22", "backend2.example.com"},
+	} {
+		attrs := lossRatioDataPoints.At(i).Attributes()
+		peerIP, _ := attrs.Get(AttrPeerIp)
+		peerName, _ := attrs.Get(AttrPeerName)
+		assert.Equal(t, expected.ip, peerIP.Str())
+		assert.Equal(t, expected.name, peerName.Str())
+	}
+
+	for _, dp := range gaugeDataPoints(metrics, "ping.rtt").All() {
+		peerIP, _ := dp.Attributes().Get(AttrPeerIp)
+		peerName, _ := dp.Attributes().Get(AttrPeerName)
+		if peerIP.Str() == "192.0.2.10" {
+			assert.Equal(t, "api.example.com", peerName.Str())
+		}
+	}
 }
@@ -0,0 +1,72 @@
+package icmpreceiver
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// targetRegistry tracks the targets of the running receivers that set
+// exclusive_targets, so that two of them cannot ping the same target. Targets
+// are claimed at start and released at shutdown, which keeps config reloads
+// working.
+type targetRegistry struct {
+	mu     sync.Mutex
+	owners map[string]component.ID
+}
+
+func newTargetRegistry() *targetRegistry {
+	return &targetRegistry{owners: make(map[string]component.ID)}
+}
+
+// claim records id as the owner of keys. Nothing is claimed when any of them
+// is owned by another receiver.
+func (r *targetRegistry) claim(id component.ID, keys []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		if owner, ok := r.owners[key]; ok && owner != id {
+			return fmt.Errorf("target %q is already pinged by receiver %q", key, owner)
+		}
+	}
+	for _, key := range keys {
+		r.owners[key] = id
+	}
+	return nil
+}
+
+// release forgets all targets owned by id.
+func (r *targetRegistry) release(id component.ID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, owner := range r.owners {
+		if owner == id {
+			delete(r.owners, key)
+		}
+	}
+}
+
+// replace makes id the owner of keys only, releasing the other targets it
+// owned. Nothing changes when any of keys is owned by another receiver.
+func (r *targetRegistry) replace(id component.ID, keys []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		if owner, ok := r.owners[key]; ok && owner != id {
+			return fmt.Errorf("target %q is already pinged by receiver %q", key, owner)
+		}
+	}
+	for key, owner := range r.owners {
+		if owner == id {
+			delete(r.owners, key)
+		}
+	}
+	for _, key := range keys {
+		r.owners[key] = id
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package icmpreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/supersun/otel-icmp-receiver/internal/metadata"
+)
+
+func TestTargetRegistry(t *testing.T) {
+	registry := newTargetRegistry()
+	first := component.NewID(metadata.Type)
+	second := component.NewIDWithName(metadata.Type, "5s")
+
+	require.NoError(t, registry.claim(first, []string{"8.8.8.8/any", "1.1.1.1/any"}))
+	// Claiming again, e.g. on restart, is allowed
+	require.NoError(t, registry.claim(first, []string{"8.8.8.8/any"}))
+
+	err := registry.claim(second, []string{"9.9.9.9/any", "1.1.1.1/any"})
+	assert.ErrorContains(t, err, `target "1.1.1.1/any" is already pinged by receiver "icmpcheck"`)
+	// A failed claim does not keep any target
+	require.NoError(t, registry.claim(first, []string{"9.9.9.9/any"}))
+
+	registry.release(first)
+	assert.NoError(t, registry.claim(second, []string{"9.9.9.9/any", "1.1.1.1/any"}))
+}
+
+func TestStartClaimsExclusiveTargets(t *testing.T) {
+	registry := newTargetRegistry()
+
+	newScraper := func(name string, targets ...Target) *pingScraper {
+		cfg := &Config{
+			ControllerConfig:     testControllerCfg,
+			MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+			Targets:              targets,
+			DefaultPingCount:     4,
+			DefaultPingTimeout:   defaultPingTimeout,
+			AddressFamily:        AddressFamilyAny,
+		}
+		settings := testSettings
+		settings.ID = component.NewIDWithName(metadata.Type, name)
+
+		pingScraper, err := newPingScraper(cfg, settings, newFakeProber(testReplies))
+		require.NoError(t, err)
+		pingScraper.registry = registry
+		return pingScraper
+	}
+
+	first := newScraper("first", Target{Target: "8.8.8.8"})
+	second := newScraper("second", Target{Target: "1.1.1.1"}, Target{Target: "::ffff:8.8.8.8"})
+
+	require.NoError(t, first.start(context.Background(), nil))
+	assert.ErrorContains(t, second.start(context.Background(), nil), `already pinged by receiver "icmpcheck/first"`)
+
+	// Targets are released on shutdown, e.g. when the config is reloaded
+	require.NoError(t, first.shutdown(context.Background()))
+	assert.NoError(t, second.start(context.Background(), nil))
+	require.NoError(t, second.shutdown(context.Background()))
+}
+
+func TestTargetRegistryReplace(t *testing.T) {
+	registry := newTargetRegistry()
+	first := component.NewID(metadata.Type)
+	second := component.NewIDWithName(metadata.Type, "5s")
+
+	require.NoError(t, registry.claim(first, []string{"8.8.8.8/any", "1.1.1.1/any"}))
+	require.NoError(t, registry.claim(second, []string{"9.9.9.9/any"}))
+
+	err := registry.replace(first, []string{"8.8.8.8/any", "9.9.9.9/any"})
+	assert.ErrorContains(t, err, `target "9.9.9.9/any" is already pinged by receiver "icmpcheck/5s"`)
+	// A failed replace keeps the previous targets
+	assert.Error(t, registry.claim(second, []string{"1.1.1.1/any"}))
+
+	require.NoError(t, registry.replace(first, []string{"8.8.8.8/any"}))
+	// Targets no longer pinged are released
+	assert.NoError(t, registry.claim(second, []string{"1.1.1.1/any"}))
+}
@@ -0,0 +1,152 @@
+package icmpreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/supersun/otel-icmp-receiver/internal/metadata"
+)
+
+const (
+	// ResolveModeFirst pings one address of a host name, preferably IPv4.
+	ResolveModeFirst = "first"
+	// ResolveModeAll pings every address a host name resolves to.
+	ResolveModeAll = "all"
+	// ResolveModeSRV pings every address of every target of an SRV record.
+	ResolveModeSRV = "srv"
+)
+
+// Resolver looks up the addresses of host names and the targets of SRV
+// records. *net.Resolver implements it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// dnsLookup is a lookup done to resolve a target, reported by the ping.dns.*
+// metrics.
+type dnsLookup struct {
+	target     Target
+	name       string
+	lookupType metadata.AttributeDNSLookupType
+	duration   time.Duration
+	err        error
+}
+
+// resolveTarget returns what to ping for a target: its address, or one target
+// per resolved address when its resolve_mode is all or srv. Resolved targets
+// remember the host name they were resolved from. Lookup failures are returned
+// as results with an error, so the other addresses are still pinged. The
+// lookups done are returned along, in order.
+func (s *pingScraper) resolveTarget(ctx context.Context, target Target) ([]targetResult, []dnsLookup) {
+	if !isHostName(target.Target) {
+		return []targetResult{{target: target, configured: target}}, nil
+	}
+
+	switch target.ResolveMode {
+	case ResolveModeAll:
+		results, lookup := s.resolveHost(ctx, target, target.Target)
+		return results, []dnsLookup{lookup}
+	case ResolveModeSRV:
+		start := time.Now()
+		_, records, err := s.resolver.LookupSRV(ctx, "", "", target.Target)
+		lookups := []dnsLookup{{
+			target:     target,
+			name:       target.Target,
+			lookupType: metadata.AttributeDNSLookupTypeSrv,
+			duration:   time.Since(start),
+			err:        err,
+		}}
+		if err != nil {
+			return []targetResult{{target: target, configured: target, err: err}}, lookups
+		}
+
+		var results []targetResult
+		seen := make(map[string]bool, len(records))
+		for _, record := range records {
+			hostResults, lookup := s.resolveHost(ctx, target, strings.TrimSuffix(record.Target, "."))
+			lookups = append(lookups, lookup)
+			for _, result := range hostResults {
+				if result.err == nil {
+					// Hosts of several records may share addresses.
+					if seen[result.target.Target] {
+						continue
+					}
+					seen[result.target.Target] = true
+				}
+				results = append(results, result)
+			}
+		}
+		return results, lookups
+	default:
+		results, lookup := s.resolveHost(ctx, target, target.Target)
+		if lookup.err != nil {
+			return results, []dnsLookup{lookup}
+		}
+		addresses := make([]string, 0, len(results))
+		for _, result := range results {
+			addresses = append(addresses, result.target.Target)
+		}
+		// Like the resolver of the standard library, prefer IPv4 addresses
+		// when any address family will do.
+		first := results[max(slices.IndexFunc(results, func(r targetResult) bool {
+			return netip.MustParseAddr(r.target.Target).Is4()
+		}), 0)]
+		first.addresses = addresses
+		return []targetResult{first}, []dnsLookup{lookup}
+	}
+}
+
+// resolveHost returns one target per address of host, in the address family
+// of target, and the lookup done.
+func (s *pingScraper) resolveHost(ctx context.Context, target Target, host string) ([]targetResult, dnsLookup) {
+	network, lookupType := "ip", metadata.AttributeDNSLookupTypeIp
+	switch target.addressFamily(s.addressFamily) {
+	case AddressFamilyIPv4:
+		network, lookupType = "ip4", metadata.AttributeDNSLookupTypeIP4
+	case AddressFamilyIPv6:
+		network, lookupType = "ip6", metadata.AttributeDNSLookupTypeIP6
+	}
+
+	start := time.Now()
+	addrs, err := s.resolver.LookupNetIP(ctx, network, host)
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no suitable address found", Name: host, IsNotFound: true}
+	}
+	lookup := dnsLookup{target: target, name: host, lookupType: lookupType, duration: time.Since(start), err: err}
+	if err != nil {
+		failed := target
+		failed.Target = host
+		err = fmt.Errorf("failed to resolve %q: %w", host, err)
+		return []targetResult{{target: failed, configured: target, err: err}}, lookup
+	}
+
+	results := make([]targetResult, 0, len(addrs))
+	for _, addr := range addrs {
+		resolved := target
+		resolved.Target = addr.Unmap().String()
+		results = append(results, targetResult{target: resolved, configured: target, peerName: host})
+	}
+	return results, lookup
+}
+
+// dnsErrorType returns the error.type attribute value of a failed lookup.
+func dnsErrorType(err error) metadata.AttributeErrorType {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return metadata.AttributeErrorTypeNotFound
+	case errors.As(err, &dnsErr) && dnsErr.IsTimeout, errors.Is(err, context.DeadlineExceeded):
+		return metadata.AttributeErrorTypeTimeout
+	case errors.As(err, &dnsErr) && dnsErr.IsTemporary:
+		return metadata.AttributeErrorTypeTemporary
+	default:
+		return metadata.AttributeErrorTypeOther
+	}
+}
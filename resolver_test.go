@@ -0,0 +1,377 @@
+package icmpreceiver
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/supersun/otel-icmp-receiver/internal/metadata"
+)
+
+// fakeResolver is an in-memory Resolver.
+type fakeResolver struct {
+	// addrs maps host names to their addresses.
+	addrs map[string][]string
+	// srvs maps SRV record names to the host names of their targets.
+	srvs map[string][]string
+}
+
+func (r fakeResolver) LookupNetIP(_ context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, ok := r.addrs[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	var result []netip.Addr
+	for _, s := range addrs {
+		addr := netip.MustParseAddr(s)
+		if network == "ip" || network == "ip4" && addr.Is4() || network == "ip6" && addr.Is6() {
+			result = append(result, addr)
+		}
+	}
+	return result, nil
+}
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	hosts, ok := r.srvs[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+
+	records := make([]*net.SRV, 0, len(hosts))
+	for _, host := range hosts {
+		records = append(records, &net.SRV{Target: host + "."})
+	}
+	return name, records, nil
+}
+
+var testResolver = fakeResolver{
+	addrs: map[string][]string{
+		"api.example.com":      {"192.0.2.10", "192.0.2.11", "2001:db8::10"},
+		"v6first.example.com":  {"2001:db8::20", "192.0.2.20"},
+		"backend1.example.com": {"192.0.2.21"},
+		"backend2.example.com": {"192.0.2.22", "192.0.2.21"},
+	},
+	srvs: map[string][]string{
+		"_icmp._udp.example.com": {"backend1.example.com", "backend2.example.com", "backend3.example.com"},
+	},
+}
+
+func TestResolveTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   Target
+		family   string
+		expected []targetResult
+		errors   []string
+		lookups  []string
+	}{
+		{
+			name:   "first",
+			target: Target{Target: "api.example.com"},
+			expected: []targetResult{{
+				target:    Target{Target: "192.0.2.10"},
+				peerName:  "api.example.com",
+				addresses: []string{"192.0.2.10", "192.0.2.11", "2001:db8::10"},
+			}},
+			lookups: []string{"ip api.example.com"},
+		},
+		{
+			name:   "first prefers IPv4",
+			target: Target{Target: "v6first.example.com"},
+			expected: []targetResult{{
+				target:    Target{Target: "192.0.2.20"},
+				peerName:  "v6first.example.com",
+				addresses: []string{"2001:db8::20", "192.0.2.20"},
+			}},
+			lookups: []string{"ip v6first.example.com"},
+		},
+		{
+			name:   "first in address family",
+			target: Target{Target: "v6first.example.com", AddressFamily: AddressFamilyIPv6},
+			expected: []targetResult{
+				{
+					target:    Target{Target: "2001:db8::20", AddressFamily: AddressFamilyIPv6},
+					peerName:  "v6first.example.com",
+					addresses: []string{"2001:db8::20"},
+				},
+			},
+			lookups: []string{"ip6 v6first.example.com"},
+		},
+		{
+			name:    "first not found",
+			target:  Target{Target: "missing.example.com"},
+			errors:  []string{`failed to resolve "missing.example.com": lookup missing.example.com: no such host`},
+			lookups: []string{"ip missing.example.com: not_found"},
+		},
+		{
+			name:     "IP address",
+			target:   Target{Target: "192.0.2.1"},
+			expected: []targetResult{{target: Target{Target: "192.0.2.1"}}},
+		},
+		{
+			name:   "all",
+			target: Target{Target: "api.example.com", ResolveMode: ResolveModeAll},
+			expected: []targetResult{
+				{target: Target{Target: "192.0.2.10", ResolveMode: ResolveModeAll}, peerName: "api.example.com"},
+				{target: Target{Target: "192.0.2.11", ResolveMode: ResolveModeAll}, peerName: "api.example.com"},
+				{target: Target{Target: "2001:db8::10", ResolveMode: ResolveModeAll}, peerName: "api.example.com"},
+			},
+			lookups: []string{"ip api.example.com"},
+		},
+		{
+			name:   "all in address family",
+			target: Target{Target: "api.example.com", ResolveMode: ResolveModeAll},
+			family: AddressFamilyIPv6,
+			expected: []targetResult{
+				{target: Target{Target: "2001:db8::10", ResolveMode: ResolveModeAll}, peerName: "api.example.com"},
+			},
+			lookups: []string{"ip6 api.example.com"},
+		},
+		{
+			name:    "all without address",
+			target:  Target{Target: "backend1.example.com", ResolveMode: ResolveModeAll, AddressFamily: AddressFamilyIPv6},
+			errors:  []string{`failed to resolve "backend1.example.com": lookup backend1.example.com: no suitable address found`},
+			lookups: []string{"ip6 backend1.example.com: not_found"},
+		},
+		{
+			name:   "srv",
+			target: Target{Target: "_icmp._udp.example.com", ResolveMode: ResolveModeSRV},
+			expected: []targetResult{
+				{target: Target{Target: "192.0.2.21", ResolveMode: ResolveModeSRV}, peerName: "backend1.example.com"},
+				{target: Target{Target: "192.0.2.22", ResolveMode: ResolveModeSRV}, peerName: "backend2.example.com"},
+			},
+			errors: []string{`failed to resolve "backend3.example.com": lookup backend3.example.com: no such host`},
+			lookups: []string{
+				"srv _icmp._udp.example.com",
+				"ip backend1.example.com",
+				"ip backend2.example.com",
+				"ip backend3.example.com: not_found",
+			},
+		},
+		{
+			name:    "srv not found",
+			target:  Target{Target: "_icmp._tcp.example.com", ResolveMode: ResolveModeSRV},
+			errors:  []string{"lookup _icmp._tcp.example.com: no such host"},
+			lookups: []string{"srv _icmp._tcp.example.com: not_found"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &pingScraper{resolver: testResolver, addressFamily: test.family}
+			resolved, lookups := s.resolveTarget(context.Background(), test.target)
+
+			var (
+				results []targetResult
+				errs    []string
+			)
+			for _, result := range resolved {
+				if result.err != nil {
+					errs = append(errs, result.err.Error())
+					var dnsErr *net.DNSError
+					assert.ErrorAs(t, result.err, &dnsErr)
+					continue
+				}
+				// Results remember the target they were resolved from.
+				assert.Equal(t, test.target, result.configured)
+				result.configured = Target{}
+				results = append(results, result)
+			}
+			assert.Equal(t, test.expected, results)
+			assert.Equal(t, test.errors, errs)
+
+			var lookupNames []string
+			for _, lookup := range lookups {
+				name := lookup.lookupType.String() + " " + lookup.name
+				if lookup.err != nil {
+					name += ": " + dnsErrorType(lookup.err).String()
+				}
+				lookupNames = append(lookupNames, name)
+			}
+			assert.Equal(t, test.lookups, lookupNames)
+		})
+	}
+}
+
+func TestDNSErrorType(t *testing.T) {
+	assert.Equal(t, metadata.AttributeErrorTypeNotFound, dnsErrorType(&net.DNSError{IsNotFound: true}))
+	assert.Equal(t, metadata.AttributeErrorTypeTimeout, dnsErrorType(&net.DNSError{IsTimeout: true}))
+	assert.Equal(t, metadata.AttributeErrorTypeTimeout, dnsErrorType(context.DeadlineExceeded))
+	assert.Equal(t, metadata.AttributeErrorTypeTemporary, dnsErrorType(&net.DNSError{IsTemporary: true}))
+	assert.Equal(t, metadata.AttributeErrorTypeOther, dnsErrorType(&net.DNSError{Err: "server answered RCodeRefused"}))
+}
+
+// stubDNSServer answers A, AAAA and SRV queries over UDP and TCP on the
+// loopback interface, to test resolution against a real DNS server.
+type stubDNSServer struct {
+	conn     net.PacketConn
+	listener net.Listener
+	// records maps fully qualified names to their resources.
+	records map[string][]dnsmessage.Resource
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func newStubDNSServer(t *testing.T, records map[string][]dnsmessage.Resource) *stubDNSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	s := &stubDNSServer{conn: conn, listener: listener, records: records}
+	go s.serveUDP()
+	go s.serveTCP()
+	return s
+}
+
+func (s *stubDNSServer) serveUDP() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if reply, ok := s.answer(buf[:n]); ok {
+			_, _ = s.conn.WriteTo(reply, addr)
+		}
+	}
+}
+
+func (s *stubDNSServer) serveTCP() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			var length [2]byte
+			if _, err := io.ReadFull(conn, length[:]); err != nil {
+				return
+			}
+			query := make([]byte, binary.BigEndian.Uint16(length[:]))
+			if _, err := io.ReadFull(conn, query); err != nil {
+				return
+			}
+			if reply, ok := s.answer(query); ok {
+				_, _ = conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(reply))), reply...))
+			}
+		}()
+	}
+}
+
+// answer returns the packed reply to a packed query.
+func (s *stubDNSServer) answer(packed []byte) ([]byte, bool) {
+	var query dnsmessage.Message
+	if err := query.Unpack(packed); err != nil || len(query.Questions) != 1 {
+		return nil, false
+	}
+	question := query.Questions[0]
+
+	s.mu.Lock()
+	s.queries = append(s.queries, question.Type.String()+" "+question.Name.String())
+	s.mu.Unlock()
+
+	reply := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true},
+		Questions: query.Questions,
+	}
+	records, ok := s.records[question.Name.String()]
+	if !ok {
+		reply.RCode = dnsmessage.RCodeNameError
+	}
+	for _, record := range records {
+		if record.Header.Type == question.Type {
+			record.Header.Name = question.Name
+			record.Header.Class = dnsmessage.ClassINET
+			reply.Answers = append(reply.Answers, record)
+		}
+	}
+
+	packed, err := reply.Pack()
+	return packed, err == nil
+}
+
+func (s *stubDNSServer) resolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", s.conn.LocalAddr().String())
+		},
+	}
+}
+
+func (s *stubDNSServer) receivedQueries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.queries)
+}
+
+func withTTL(record dnsmessage.Resource, ttl uint32) dnsmessage.Resource {
+	record.Header.TTL = ttl
+	return record
+}
+
+func aRecord(ip string) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeA, TTL: 60},
+		Body:   &dnsmessage.AResource{A: netip.MustParseAddr(ip).As4()},
+	}
+}
+
+func aaaaRecord(ip string) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeAAAA, TTL: 60},
+		Body:   &dnsmessage.AAAAResource{AAAA: netip.MustParseAddr(ip).As16()},
+	}
+}
+
+func srvRecord(target string) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeSRV, TTL: 60},
+		Body:   &dnsmessage.SRVResource{Priority: 10, Weight: 10, Target: dnsmessage.MustNewName(target)},
+	}
+}
+
+func TestResolveTargetWithStubDNSServer(t *testing.T) {
+	server := newStubDNSServer(
+		t, map[string][]dnsmessage.Resource{
+			"_icmp._udp.example.test.": {srvRecord("backend1.example.test.")},
+			"backend1.example.test.":   {aRecord("192.0.2.31"), aRecord("192.0.2.32"), aaaaRecord("2001:db8::31")},
+		},
+	)
+	s := &pingScraper{resolver: server.resolver(), addressFamily: AddressFamilyIPv4}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results, _ := s.resolveTarget(ctx, Target{Target: "_icmp._udp.example.test.", ResolveMode: ResolveModeSRV})
+
+	var addrs []string
+	for _, result := range results {
+		require.NoError(t, result.err)
+		assert.Equal(t, "backend1.example.test", result.peerName)
+		addrs = append(addrs, result.target.Target)
+	}
+	assert.ElementsMatch(t, []string{"192.0.2.31", "192.0.2.32"}, addrs)
+
+	queries := strings.Join(server.receivedQueries(), ",")
+	assert.Contains(t, queries, "TypeSRV _icmp._udp.example.test.")
+	assert.Contains(t, queries, "TypeA backend1.example.test.")
+	assert.NotContains(t, queries, "TypeAAAA")
+}
@@ -0,0 +1,80 @@
+package icmpreceiver
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// SchedulePeriodic pings a target with an interval at a fixed period, the
+	// periodic sampling of RFC 3432.
+	SchedulePeriodic = "periodic"
+	// SchedulePoisson draws the time between two pings of a target with an
+	// interval from an exponential distribution of mean interval, the Poisson
+	// sampling of RFC 2330.
+	SchedulePoisson = "poisson"
+)
+
+// sampler draws the start offsets and the intervals of probes. Draws only
+// depend on its seed, so that the same seed reproduces them.
+type sampler struct {
+	seed uint64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newSampler(seed uint64) *sampler {
+	return &sampler{seed: seed, rand: rand.New(rand.NewPCG(seed, 0))}
+}
+
+// offset returns a random delay below spread, for probes started at every
+// scrape. Successive calls draw different delays.
+func (s *sampler) offset(spread time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return randomOffset(s.rand, spread)
+}
+
+// targetRand returns a random generator of its own for target, so that its
+// draws do not depend on the draws made for other targets.
+func (s *sampler) targetRand(target string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(target))
+	return rand.New(rand.NewPCG(s.seed, h.Sum64()))
+}
+
+// randomOffset returns a delay uniformly distributed in [0, spread).
+func randomOffset(r *rand.Rand, spread time.Duration) time.Duration {
+	if spread <= 0 {
+		return 0
+	}
+	return time.Duration(r.Int64N(int64(spread)))
+}
+
+// nextInterval returns the time between the start of a run of a target with
+// an interval and the start of its next run.
+func nextInterval(r *rand.Rand, target Target) time.Duration {
+	if target.Schedule == SchedulePoisson {
+		return time.Duration(r.ExpFloat64() * float64(*target.Interval))
+	}
+	return *target.Interval
+}
+
+// sleepContext waits for d, and reports false if ctx ended first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
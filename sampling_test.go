@@ -0,0 +1,65 @@
+package icmpreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplerIsReproducible(t *testing.T) {
+	draw := func(s *sampler) []time.Duration {
+		var offsets []time.Duration
+		for range 5 {
+			offsets = append(offsets, s.offset(time.Second))
+		}
+		return append(offsets, randomOffset(s.targetRand("192.0.2.1"), time.Second))
+	}
+
+	first := draw(newSampler(42))
+	assert.Equal(t, first, draw(newSampler(42)), "The same seed draws the same offsets")
+	assert.NotEqual(t, first, draw(newSampler(43)))
+	for _, offset := range first {
+		assert.GreaterOrEqual(t, offset, time.Duration(0))
+		assert.Less(t, offset, time.Second)
+	}
+
+	s := newSampler(42)
+	assert.NotEqual(
+		t, randomOffset(s.targetRand("192.0.2.1"), time.Hour), randomOffset(s.targetRand("192.0.2.2"), time.Hour),
+		"Targets draw their own offsets",
+	)
+	assert.Zero(t, s.offset(0))
+}
+
+func TestNextInterval(t *testing.T) {
+	r := newSampler(1).targetRand("192.0.2.1")
+	periodic := intervalTarget("192.0.2.1", time.Second)
+	assert.Equal(t, time.Second, nextInterval(r, periodic))
+
+	poisson := intervalTarget("192.0.2.1", time.Second)
+	poisson.Schedule = SchedulePoisson
+	const draws = 20000
+	var sum time.Duration
+	distinct := make(map[time.Duration]bool)
+	for range draws {
+		interval := nextInterval(r, poisson)
+		assert.GreaterOrEqual(t, interval, time.Duration(0))
+		sum += interval
+		distinct[interval] = true
+	}
+	// Exponentially distributed intervals have the interval as their mean
+	assert.InDelta(t, float64(time.Second), float64(sum/draws), float64(50*time.Millisecond))
+	assert.Greater(t, len(distinct), draws/2)
+}
+
+func TestSleepContext(t *testing.T) {
+	assert.True(t, sleepContext(context.Background(), time.Millisecond))
+	assert.True(t, sleepContext(context.Background(), 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, sleepContext(ctx, time.Hour))
+	assert.False(t, sleepContext(ctx, 0))
+}
@@ -0,0 +1,150 @@
+package icmpreceiver
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// scheduler pings the targets that have their own interval in the background,
+// each on its own cadence, and keeps their results until the next scrape
+// collects them.
+type scheduler struct {
+	// run resolves and pings a target.
+	run func(ctx context.Context, target Target) ([]targetResult, []dnsLookup)
+	// sampler draws the first run offset and the intervals of every target,
+	// below spread unless the target sets its own.
+	sampler *sampler
+	spread  time.Duration
+
+	mu      sync.Mutex
+	results []targetResult
+	lookups []dnsLookup
+	// ctx interrupts the pings. It is nil while the scheduler is stopped.
+	ctx context.Context
+	// loops are the loops of the current targets.
+	loops []*targetLoop
+	wg    sync.WaitGroup
+}
+
+// targetLoop is the loop pinging a target, stopped by closing done.
+type targetLoop struct {
+	target Target
+	done   chan struct{}
+}
+
+func newScheduler(
+	run func(ctx context.Context, target Target) ([]targetResult, []dnsLookup), sampler *sampler, spread time.Duration,
+) *scheduler {
+	return &scheduler{run: run, sampler: sampler, spread: spread}
+}
+
+// start pings each of the targets every interval from now on. Pings are
+// interrupted once ctx ends.
+func (s *scheduler) start(ctx context.Context, targets []Target) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopLoops()
+	s.ctx = ctx
+	s.schedule(targets)
+}
+
+// update replaces the scheduled targets, if the scheduler is started. Targets
+// that did not change keep their loop and cadence. Runs of the removed targets
+// that are in flight complete, and their results are kept.
+func (s *scheduler) update(targets []Target) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil {
+		s.schedule(targets)
+	}
+}
+
+// schedule keeps the loops of the targets that are already scheduled, starts
+// one per new target and stops the loops of the targets no longer given.
+// s.mu must be held.
+func (s *scheduler) schedule(targets []Target) {
+	previous := make(map[string][]*targetLoop, len(s.loops))
+	for _, l := range s.loops {
+		previous[l.target.Target] = append(previous[l.target.Target], l)
+	}
+
+	loops := make([]*targetLoop, 0, len(targets))
+	for _, target := range targets {
+		same := previous[target.Target]
+		if i := slices.IndexFunc(same, func(l *targetLoop) bool { return reflect.DeepEqual(l.target, target) }); i != -1 {
+			loops = append(loops, same[i])
+			previous[target.Target] = slices.Delete(same, i, i+1)
+			continue
+		}
+		ctx, l := s.ctx, &targetLoop{target: target, done: make(chan struct{})}
+		s.wg.Go(func() { s.loop(ctx, l.done, target) })
+		loops = append(loops, l)
+	}
+	for _, removed := range previous {
+		for _, l := range removed {
+			close(l.done)
+		}
+	}
+	s.loops = loops
+}
+
+// stopLoops stops the loops of the current targets. s.mu must be held.
+func (s *scheduler) stopLoops() {
+	for _, l := range s.loops {
+		close(l.done)
+	}
+	s.loops = nil
+}
+
+// loop pings target after a random offset below its spread, then every
+// interval until done is closed or ctx ends. Intervals are counted from the
+// start of a run, a run that lasts longer is followed by the next right away.
+func (s *scheduler) loop(ctx context.Context, done <-chan struct{}, target Target) {
+	rnd := s.sampler.targetRand(target.Target)
+	timer := time.NewTimer(randomOffset(rnd, target.spread(s.spread)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		next := time.Now().Add(nextInterval(rnd, target))
+		results, lookups := s.run(ctx, target)
+		s.mu.Lock()
+		s.results = append(s.results, results...)
+		s.lookups = append(s.lookups, lookups...)
+		s.mu.Unlock()
+		timer.Reset(time.Until(next))
+	}
+}
+
+// collect returns the results and lookups completed since the last call, in
+// the order they completed.
+func (s *scheduler) collect() ([]targetResult, []dnsLookup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results, lookups := s.results, s.lookups
+	s.results, s.lookups = nil, nil
+	return results, lookups
+}
+
+// stop stops all loops and waits for them to return. Pings still running are
+// only interrupted by the end of the context given to start.
+func (s *scheduler) stop() {
+	s.mu.Lock()
+	s.stopLoops()
+	s.ctx = nil
+	s.mu.Unlock()
+	s.wg.Wait()
+}
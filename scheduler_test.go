@@ -0,0 +1,127 @@
+package icmpreceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRun is a scheduler run that records the targets it runs.
+type countingRun struct {
+	mu   sync.Mutex
+	runs map[string]int
+}
+
+func (r *countingRun) run(_ context.Context, target Target) ([]targetResult, []dnsLookup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[target.Target]++
+	return []targetResult{{target: target}}, []dnsLookup{{target: target, name: target.Target}}
+}
+
+func (r *countingRun) count(target string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runs[target]
+}
+
+func intervalTarget(target string, interval time.Duration) Target {
+	return Target{Target: target, Interval: &interval}
+}
+
+func TestScheduler(t *testing.T) {
+	counting := &countingRun{runs: make(map[string]int)}
+	s := newScheduler(counting.run, newSampler(1), 0)
+
+	// Targets are not pinged before start
+	s.update([]Target{intervalTarget("192.0.2.1", time.Millisecond)})
+	time.Sleep(10 * time.Millisecond)
+	assert.Zero(t, counting.count("192.0.2.1"))
+
+	s.start(context.Background(), []Target{
+		intervalTarget("192.0.2.1", 10*time.Millisecond), intervalTarget("192.0.2.2", time.Hour),
+	})
+	require.Eventually(t, func() bool { return counting.count("192.0.2.1") >= 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, counting.count("192.0.2.2"), "Targets are pinged right away, then every interval")
+
+	results, lookups := s.collect()
+	assert.GreaterOrEqual(t, len(results), 4)
+	assert.Len(t, lookups, len(results))
+	results, _ = s.collect()
+	assert.LessOrEqual(t, len(results), 1, "Collected results are only returned once")
+
+	s.update([]Target{intervalTarget("192.0.2.3", time.Hour)})
+	require.Eventually(t, func() bool { return counting.count("192.0.2.3") == 1 }, time.Second, time.Millisecond)
+	before := counting.count("192.0.2.1")
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, before, counting.count("192.0.2.1"), "Replaced targets are not pinged anymore")
+
+	s.stop()
+	s.stop()
+}
+
+func TestSchedulerUpdateKeepsUnchangedTargets(t *testing.T) {
+	counting := &countingRun{runs: make(map[string]int)}
+	s := newScheduler(counting.run, newSampler(1), 0)
+	defer s.stop()
+
+	s.start(context.Background(), []Target{
+		intervalTarget("192.0.2.1", time.Hour), intervalTarget("192.0.2.2", time.Hour),
+	})
+	require.Eventually(
+		t, func() bool { return counting.count("192.0.2.1") == 1 && counting.count("192.0.2.2") == 1 }, time.Second,
+		time.Millisecond,
+	)
+
+	// Only the added target starts, the unchanged one keeps its cadence
+	s.update([]Target{intervalTarget("192.0.2.1", time.Hour), intervalTarget("192.0.2.3", time.Hour)})
+	require.Eventually(t, func() bool { return counting.count("192.0.2.3") == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, counting.count("192.0.2.1"))
+	require.Len(t, s.loops, 2)
+
+	// A target whose settings changed starts over
+	s.update([]Target{intervalTarget("192.0.2.1", 2*time.Hour), intervalTarget("192.0.2.3", time.Hour)})
+	require.Eventually(t, func() bool { return counting.count("192.0.2.1") == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, counting.count("192.0.2.3"))
+}
+
+func TestSchedulerStopsWithContext(t *testing.T) {
+	counting := &countingRun{runs: make(map[string]int)}
+	s := newScheduler(counting.run, newSampler(1), 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.start(ctx, []Target{intervalTarget("192.0.2.1", time.Millisecond)})
+	require.Eventually(t, func() bool { return counting.count("192.0.2.1") > 0 }, time.Second, time.Millisecond)
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("loops did not return once the context ended")
+	}
+	s.stop()
+}
+
+func TestSchedulerWithSpread(t *testing.T) {
+	counting := &countingRun{runs: make(map[string]int)}
+	s := newScheduler(counting.run, newSampler(1), time.Hour)
+	defer s.stop()
+
+	spread := time.Duration(0)
+	poisson := intervalTarget("192.0.2.2", time.Millisecond)
+	poisson.Spread, poisson.Schedule = &spread, SchedulePoisson
+	s.start(context.Background(), []Target{intervalTarget("192.0.2.1", 2*time.Hour), poisson})
+
+	require.Eventually(t, func() bool { return counting.count("192.0.2.2") >= 3 }, time.Second, time.Millisecond)
+	assert.Zero(t, counting.count("192.0.2.1"), "The first run waits for an offset below the spread")
+}
@@ -0,0 +1,151 @@
+package icmpreceiver
+
+import (
+	"maps"
+	"time"
+)
+
+const (
+	// StateUp is the state of a target that answers, with a loss ratio below
+	// the loss threshold.
+	StateUp = "up"
+	// StateDegraded is the state of a target that answers, with a loss ratio
+	// at or above the loss threshold.
+	StateDegraded = "degraded"
+	// StateDown is the state of a target that does not answer, or whose host
+	// name cannot be resolved.
+	StateDown = "down"
+	// stateUnknown is the state of a target before its first results.
+	stateUnknown = "unknown"
+)
+
+const (
+	AttrState          = "ping.state"
+	AttrStatePrevious  = "ping.state.previous"
+	AttrOutageDuration = "ping.outage.duration_ms"
+	AttrLossRatio      = "ping.loss.ratio"
+)
+
+const defaultLossThreshold = 0.5
+
+// stateKey identifies the state of a target: the key of the target as
+// configured, and the name it is reported under. The address is only set for
+// the addresses of a host name that resolves to several pinged ones.
+type stateKey struct {
+	target, peerName, address string
+}
+
+// targetState is the reachability state of a target.
+type targetState struct {
+	state string
+	// pending is the state observed by the last count results, once it
+	// differs from state. pendingSince is the time of the first of them.
+	pending      string
+	pendingSince time.Time
+	count        int
+	// outageStart is when the target stopped being up, zero while it is up.
+	outageStart time.Time
+}
+
+// stateChange is the move of a target from one state to another.
+type stateChange struct {
+	previous, current string
+	at                time.Time
+	// outage is how long the target has not been up, until it recovered when
+	// current is up. It is zero when previous is up.
+	outage time.Duration
+}
+
+// stateTracker follows the reachability state of targets across scrapes. A
+// target changes state once the results of failureThreshold consecutive
+// scrapes, or of recoveryThreshold ones to come back up, agree on a new state.
+// A target without a state yet is up from its first result that finds it up.
+type stateTracker struct {
+	lossThreshold     float64
+	failureThreshold  int
+	recoveryThreshold int
+
+	states map[stateKey]*targetState
+}
+
+func newStateTracker(cfg StateChangesConfig) *stateTracker {
+	return &stateTracker{
+		lossThreshold:     cfg.LossThreshold,
+		failureThreshold:  max(cfg.FailureThreshold, 1),
+		recoveryThreshold: max(cfg.RecoveryThreshold, 1),
+		states:            make(map[stateKey]*targetState),
+	}
+}
+
+// observe updates the state of a target with a result taken at the given
+// time, and returns the change it causes, if any. Targets found up by their
+// first results are not reported as a change.
+func (t *stateTracker) observe(key stateKey, reachable bool, lossRatio float64, at time.Time) (stateChange, bool) {
+	observed := StateUp
+	switch {
+	case !reachable:
+		observed = StateDown
+	case t.lossThreshold > 0 && lossRatio >= t.lossThreshold:
+		observed = StateDegraded
+	}
+
+	if key.address != "" {
+		// The addresses of a host name take over the state it was given while
+		// it could not be resolved.
+		unresolved := stateKey{target: key.target, peerName: key.peerName}
+		if st, ok := t.states[unresolved]; ok {
+			delete(t.states, unresolved)
+			t.states[key] = st
+		}
+	}
+	st, ok := t.states[key]
+	if !ok {
+		st = &targetState{state: stateUnknown}
+		t.states[key] = st
+	}
+	if observed == st.state {
+		st.pending, st.count = "", 0
+		return stateChange{}, false
+	}
+	if observed != st.pending {
+		st.pending, st.pendingSince, st.count = observed, at, 0
+	}
+	st.count++
+
+	threshold := t.failureThreshold
+	switch {
+	case observed == StateUp && st.state == stateUnknown:
+		// Nothing to recover from yet.
+		threshold = 1
+	case observed == StateUp:
+		threshold = t.recoveryThreshold
+	}
+	if st.count < threshold {
+		return stateChange{}, false
+	}
+
+	change := stateChange{previous: st.state, current: observed, at: at}
+	switch {
+	case observed == StateUp:
+		// The outage ended with the first result that found the target up.
+		if !st.outageStart.IsZero() {
+			change.outage = st.pendingSince.Sub(st.outageStart)
+		}
+		st.outageStart = time.Time{}
+	case st.outageStart.IsZero():
+		st.outageStart = st.pendingSince
+	default:
+		change.outage = at.Sub(st.outageStart)
+	}
+	st.state, st.pending, st.count = observed, "", 0
+
+	if change.previous == stateUnknown && change.current == StateUp {
+		return stateChange{}, false
+	}
+	return change, true
+}
+
+// forget drops the states of the targets for which removed returns true.
+func (t *stateTracker) forget(removed func(target string) bool) {
+	maps.DeleteFunc(t.states, func(key stateKey, _ *targetState) bool { return removed(key.target) })
+}
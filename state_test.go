@@ -0,0 +1,90 @@
+package icmpreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateTracker(t *testing.T) {
+	tracker := newStateTracker(StateChangesConfig{LossThreshold: .5})
+	key := stateKey{target: "192.0.2.1"}
+	at := func(seconds int) time.Time { return fakeTimestamp.Add(time.Duration(seconds) * time.Second) }
+
+	_, changed := tracker.observe(key, true, 0, at(0))
+	assert.False(t, changed, "Targets found up by their first results are not reported")
+	_, changed = tracker.observe(key, true, .25, at(10))
+	assert.False(t, changed)
+
+	change, changed := tracker.observe(key, true, .5, at(20))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: StateUp, current: StateDegraded, at: at(20)}, change)
+
+	change, changed = tracker.observe(key, false, 1, at(30))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: StateDegraded, current: StateDown, at: at(30), outage: 10 * time.Second}, change)
+
+	change, changed = tracker.observe(key, true, 0, at(40))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: StateDown, current: StateUp, at: at(40), outage: 20 * time.Second}, change)
+
+	// Other targets have their own state
+	change, changed = tracker.observe(stateKey{target: "192.0.2.2"}, false, 1, at(40))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: stateUnknown, current: StateDown, at: at(40)}, change)
+}
+
+func TestStateTrackerUnresolvedHostName(t *testing.T) {
+	tracker := newStateTracker(StateChangesConfig{})
+	unresolved := stateKey{target: "pool.example.com/any", peerName: "pool.example.com"}
+	first, second := unresolved, unresolved
+	first.address, second.address = "192.0.2.1", "192.0.2.2"
+	at := func(seconds int) time.Time { return fakeTimestamp.Add(time.Duration(seconds) * time.Second) }
+
+	_, changed := tracker.observe(first, true, 0, at(0))
+	assert.False(t, changed)
+	_, changed = tracker.observe(unresolved, false, 1, at(10))
+	assert.True(t, changed)
+
+	// The first address found takes over the state of the host name
+	change, changed := tracker.observe(first, true, 0, at(20))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: StateDown, current: StateUp, at: at(20), outage: 10 * time.Second}, change)
+	_, changed = tracker.observe(second, true, 0, at(20))
+	assert.False(t, changed)
+	assert.NotContains(t, tracker.states, unresolved)
+}
+
+func TestStateTrackerThresholds(t *testing.T) {
+	tracker := newStateTracker(StateChangesConfig{FailureThreshold: 2, RecoveryThreshold: 3})
+	key := stateKey{target: "192.0.2.1"}
+	at := func(seconds int) time.Time { return fakeTimestamp.Add(time.Duration(seconds) * time.Second) }
+
+	_, changed := tracker.observe(key, true, 0, at(0))
+	assert.False(t, changed)
+	_, changed = tracker.observe(key, true, .9, at(10))
+	assert.False(t, changed, "Targets are never degraded without a loss threshold")
+
+	// A single failure is not enough to change state
+	_, changed = tracker.observe(key, false, 1, at(20))
+	assert.False(t, changed)
+	_, changed = tracker.observe(key, true, 0, at(30))
+	assert.False(t, changed)
+
+	_, changed = tracker.observe(key, false, 1, at(40))
+	assert.False(t, changed)
+	change, changed := tracker.observe(key, false, 1, at(50))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: StateUp, current: StateDown, at: at(50)}, change)
+
+	for _, seconds := range []int{60, 70} {
+		_, changed = tracker.observe(key, true, 0, at(seconds))
+		assert.False(t, changed)
+	}
+	// The outage lasts from the first failure to the first reply
+	change, changed = tracker.observe(key, true, 0, at(80))
+	require.True(t, changed)
+	assert.Equal(t, stateChange{previous: StateDown, current: StateUp, at: at(80), outage: 20 * time.Second}, change)
+}
@@ -0,0 +1,228 @@
+package icmpreceiver
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+)
+
+const (
+	// ModeBurst sends ping_count echo requests to every target at each scrape.
+	ModeBurst = "burst"
+	// ModeContinuous keeps pinging every target at a steady rate, and reports
+	// the packets of the window since the previous scrape.
+	ModeContinuous = "continuous"
+)
+
+// packetWindow accumulates the packets of a continuous probe between two
+// scrapes. Echo requests are settled, as received or lost, once their reply
+// comes back or their timeout expires, so requests still awaiting a reply at a
+// scrape are counted by the next one.
+type packetWindow struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	start time.Time
+	// pending holds the send time of the requests awaiting a reply, by sequence
+	// number.
+	pending                    map[int]time.Time
+	sent, received, duplicates int
+	packets                    []*Packet
+}
+
+func newPacketWindow(timeout time.Duration, start time.Time) *packetWindow {
+	return &packetWindow{timeout: timeout, start: start, pending: make(map[int]time.Time)}
+}
+
+func (w *packetWindow) OnSend(seq int, at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[seq] = at
+}
+
+func (w *packetWindow) OnRecv(pkt *Packet) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Replies to requests already counted as lost are ignored.
+	if _, ok := w.pending[pkt.Seq]; !ok {
+		return
+	}
+	delete(w.pending, pkt.Seq)
+	w.sent++
+	w.received++
+	w.packets = append(w.packets, pkt)
+}
+
+func (w *packetWindow) OnDuplicateRecv(*Packet) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.duplicates++
+}
+
+// collect settles the requests that timed out by now, and returns the packets
+// and statistics of the window before starting a new one. The address of the
+// statistics is left for the caller to set.
+func (w *packetWindow) collect(now time.Time) (res *ProbeResult, start time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for seq, at := range w.pending {
+		if !now.Before(at.Add(w.timeout)) {
+			delete(w.pending, seq)
+			w.sent++
+		}
+	}
+
+	rtts := make([]time.Duration, 0, len(w.packets))
+	for _, pkt := range w.packets {
+		rtts = append(rtts, pkt.Rtt)
+	}
+	stats := windowStatistics(w.sent, rtts)
+	stats.PacketsRecvDuplicates = w.duplicates
+	res = &ProbeResult{Packets: w.packets, Stats: stats, StatsTimestamp: now}
+
+	start = w.start
+	w.start, w.sent, w.received, w.duplicates, w.packets = now, 0, 0, 0, nil
+	return res, start
+}
+
+// windowStatistics computes the statistics of a window the way pro-bing does
+// for a whole run.
+func windowStatistics(sent int, rtts []time.Duration) *probing.Statistics {
+	stats := &probing.Statistics{PacketsSent: sent, PacketsRecv: len(rtts), Rtts: rtts}
+	if sent > 0 {
+		stats.PacketLoss = float64(sent-len(rtts)) / float64(sent) * 100
+	}
+	if len(rtts) == 0 {
+		return stats
+	}
+
+	stats.MinRtt, stats.MaxRtt = rtts[0], rtts[0]
+	var sum time.Duration
+	for _, rtt := range rtts {
+		stats.MinRtt = min(stats.MinRtt, rtt)
+		stats.MaxRtt = max(stats.MaxRtt, rtt)
+		sum += rtt
+	}
+	stats.AvgRtt = sum / time.Duration(len(rtts))
+
+	var sumSquares float64
+	for _, rtt := range rtts {
+		diff := float64(rtt - stats.AvgRtt)
+		sumSquares += diff * diff
+	}
+	stats.StdDevRtt = time.Duration(math.Sqrt(sumSquares / float64(len(rtts))))
+	return stats
+}
+
+// stream is a long-lived probe of a single address.
+type stream struct {
+	window *packetWindow
+	cancel context.CancelFunc
+	done   chan struct{}
+	// err is the error the probe stopped with, once done is closed.
+	err error
+}
+
+// streams runs the continuous probes of a scraper, one per probe request.
+type streams struct {
+	prober StreamProber
+	// sampler draws the start offsets of the probes.
+	sampler *sampler
+
+	mu      sync.Mutex
+	running map[ProbeRequest]*stream
+	wg      sync.WaitGroup
+}
+
+func newStreams(prober StreamProber, sampler *sampler) *streams {
+	return &streams{prober: prober, sampler: sampler, running: make(map[ProbeRequest]*stream)}
+}
+
+// collect returns the window of the probe of req since the last call, and
+// starts the probe after a random offset below spread if it is not running
+// yet. It returns a nil result while no request of the probe was settled. A
+// probe that stopped returns its last window along with its error, and is
+// started again by the next call.
+func (s *streams) collect(ctx context.Context, req ProbeRequest, spread time.Duration) (*pingResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.running[req]
+	if !ok {
+		s.start(ctx, req, s.sampler.offset(spread))
+		return nil, nil
+	}
+	var err error
+	select {
+	case <-st.done:
+		delete(s.running, req)
+		err = st.err
+	default:
+	}
+
+	res, start := st.window.collect(time.Now())
+	if res.Stats.PacketsSent == 0 {
+		return nil, err
+	}
+	// Addresses are resolved by the scraper before probes start.
+	addr := net.IPAddr{IP: net.ParseIP(req.Target)}
+	if parsed, err := netip.ParseAddr(req.Target); err == nil {
+		addr = net.IPAddr{IP: parsed.AsSlice(), Zone: parsed.Zone()}
+	}
+	res.Stats.Addr, res.Stats.IPAddr = req.Target, &addr
+	for _, pkt := range res.Packets {
+		if pkt.IPAddr == nil {
+			pkt.IPAddr = &addr
+		}
+	}
+	return &pingResult{ProbeResult: res, source: req.Source, start: start}, err
+}
+
+// start runs the probe of req, after offset, until ctx ends or the probe is
+// stopped. s.mu must be held.
+func (s *streams) start(ctx context.Context, req ProbeRequest, offset time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	st := &stream{window: newPacketWindow(req.Timeout, time.Now()), cancel: cancel, done: make(chan struct{})}
+	s.running[req] = st
+	s.wg.Go(func() {
+		defer close(st.done)
+		if sleepContext(ctx, offset) {
+			st.err = s.prober.Stream(ctx, req, st.window)
+		}
+	})
+}
+
+// has reports whether the probe of req was started.
+func (s *streams) has(req ProbeRequest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.running[req]
+	return ok
+}
+
+// retain stops the probes whose request is not in reqs.
+func (s *streams) retain(reqs map[ProbeRequest]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for req, st := range s.running {
+		if !reqs[req] {
+			st.cancel()
+			delete(s.running, req)
+		}
+	}
+}
+
+// stop stops all probes and waits for them to return.
+func (s *streams) stop() {
+	s.retain(nil)
+	s.wg.Wait()
+}
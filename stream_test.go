@@ -0,0 +1,129 @@
+package icmpreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := newPacketWindow(time.Second, start)
+	reply := func(seq int, rtt time.Duration) *Packet {
+		return &Packet{Packet: &probing.Packet{Seq: seq, Rtt: rtt}}
+	}
+
+	for seq := range 5 {
+		window.OnSend(seq, start.Add(time.Duration(seq)*time.Second))
+	}
+	window.OnRecv(reply(0, 10*time.Millisecond))
+	window.OnRecv(reply(2, 30*time.Millisecond))
+	window.OnDuplicateRecv(reply(2, 31*time.Millisecond))
+	window.OnRecv(reply(4, 20*time.Millisecond))
+
+	// Requests 1 and 3 timed out, no request awaits a reply
+	res, windowStart := window.collect(start.Add(5 * time.Second))
+	assert.Equal(t, start, windowStart)
+	assert.Equal(t, start.Add(5*time.Second), res.StatsTimestamp)
+	assert.Len(t, res.Packets, 3)
+	assert.Equal(t, 5, res.Stats.PacketsSent)
+	assert.Equal(t, 3, res.Stats.PacketsRecv)
+	assert.Equal(t, 1, res.Stats.PacketsRecvDuplicates)
+	assert.InDelta(t, 40., res.Stats.PacketLoss, 1e-9)
+	assert.Equal(t, 10*time.Millisecond, res.Stats.MinRtt)
+	assert.Equal(t, 30*time.Millisecond, res.Stats.MaxRtt)
+	assert.Equal(t, 20*time.Millisecond, res.Stats.AvgRtt)
+
+	// A request awaiting its reply at a scrape is counted by the next one
+	window.OnSend(5, start.Add(5500*time.Millisecond))
+	window.OnSend(6, start.Add(6*time.Second))
+	res, windowStart = window.collect(start.Add(6600 * time.Millisecond))
+	assert.Equal(t, start.Add(5*time.Second), windowStart)
+	assert.Equal(t, 1, res.Stats.PacketsSent)
+	assert.Equal(t, 0, res.Stats.PacketsRecv)
+
+	// Late replies of requests counted as lost are ignored
+	window.OnRecv(reply(5, 1100*time.Millisecond))
+	window.OnRecv(reply(6, 700*time.Millisecond))
+	res, _ = window.collect(start.Add(7 * time.Second))
+	assert.Equal(t, 1, res.Stats.PacketsSent)
+	assert.Equal(t, 1, res.Stats.PacketsRecv)
+	assert.Equal(t, 0, res.Stats.PacketsRecvDuplicates)
+	assert.Zero(t, res.Stats.PacketLoss)
+}
+
+func TestStreams(t *testing.T) {
+	prober := newFakeProber(testReplies)
+	s := newStreams(prober, newSampler(1))
+	req := ProbeRequest{Target: "8.8.8.8", Interval: time.Millisecond, Timeout: time.Second}
+
+	// The first call starts the probe
+	res, err := s.collect(context.Background(), req, 0)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+
+	require.Eventually(
+		t, func() bool {
+			res, err = s.collect(context.Background(), req, 0)
+			return err == nil && res != nil
+		}, time.Second, time.Millisecond,
+	)
+	assert.Equal(t, "8.8.8.8", res.Stats.Addr)
+	assert.Equal(t, "8.8.8.8", res.Stats.IPAddr.String())
+	assert.Zero(t, res.Stats.PacketLoss)
+	assert.Equal(t, []string{"8.8.8.8"}, prober.probedTargets(), "The probe outlives collects")
+
+	s.retain(nil)
+	assert.Empty(t, s.running)
+	s.stop()
+}
+
+func TestStreamsReportWindowOfFailedProbes(t *testing.T) {
+	failure := errors.New("sendto: network is unreachable")
+	prober := newFakeProber(map[string]fakeReply{
+		"192.0.2.50": {ip: "192.0.2.50", rtts: []time.Duration{10 * time.Millisecond}, err: failure, failAfter: 3},
+	})
+	s := newStreams(prober, newSampler(1))
+	defer s.stop()
+	req := ProbeRequest{Target: "192.0.2.50", Interval: time.Millisecond, Timeout: time.Second}
+
+	_, err := s.collect(context.Background(), req, 0)
+	require.NoError(t, err)
+	<-s.running[req].done
+
+	// The packets exchanged before the probe failed are returned along with its error
+	res, err := s.collect(context.Background(), req, 0)
+	require.ErrorIs(t, err, failure)
+	require.NotNil(t, res)
+	assert.Equal(t, 3, res.Stats.PacketsSent)
+	assert.Equal(t, 3, res.Stats.PacketsRecv)
+	assert.Len(t, res.Packets, 3)
+	assert.Equal(t, "192.0.2.50", res.Stats.Addr)
+}
+
+func TestStreamsRestartFailedProbes(t *testing.T) {
+	prober := newFakeProber(testReplies)
+	s := newStreams(prober, newSampler(1))
+	defer s.stop()
+	req := ProbeRequest{Target: "192.0.2.3", Interval: time.Millisecond, Timeout: time.Second}
+
+	_, err := s.collect(context.Background(), req, 0)
+	require.NoError(t, err)
+	require.Eventually(
+		t, func() bool {
+			_, err = s.collect(context.Background(), req, 0)
+			return err != nil
+		}, time.Second, time.Millisecond,
+	)
+	assert.True(t, errors.Is(err, testReplies["broken.example.com"].err))
+
+	// The next collect starts the probe again
+	_, err = s.collect(context.Background(), req, 0)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(prober.probedTargets()) == 2 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,146 @@
+package icmpreceiver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	// maxHostNameLength and maxLabelLength are the limits of RFC 1035, in
+	// bytes of the ASCII form of a name, without its trailing dot.
+	maxHostNameLength = 253
+	maxLabelLength    = 63
+)
+
+// parseTarget checks that target is an IP address, a CIDR prefix, a range of
+// addresses or an RFC 1123 host name, and returns the form to ping. Internationalized host
+// names are converted to punycode, other targets are returned as is.
+func parseTarget(target string) (string, error) {
+	switch {
+	case target == "":
+		return "", errors.New("cannot be empty")
+	case strings.ContainsFunc(target, unicode.IsSpace):
+		return "", errors.New("cannot contain whitespace")
+	case strings.Contains(target, "://"):
+		return "", errors.New("must be a host name or an IP address, not a URL")
+	}
+
+	if _, err := netip.ParseAddr(target); err == nil {
+		return target, nil
+	}
+	if isAddressRange(target) {
+		if _, err := parseAddressRange(target); err != nil {
+			return "", err
+		}
+		return target, nil
+	}
+	if host, port, err := net.SplitHostPort(target); err == nil {
+		return "", fmt.Errorf("cannot contain a port, ICMP has none: use %q instead of port %s", host, port)
+	}
+
+	return parseHostName(target)
+}
+
+// parseSRVName checks that name is the name of an SRV record, made of service
+// and protocol labels followed by a host name, e.g. _icmp._udp.example.com, and
+// returns the form to look up.
+func parseSRVName(name string) (string, error) {
+	service, rest, _ := strings.Cut(name, ".")
+	proto, host, _ := strings.Cut(rest, ".")
+	if !strings.HasPrefix(service, "_") || !strings.HasPrefix(proto, "_") || host == "" {
+		return "", errors.New("must be an SRV record name, e.g. _icmp._udp.example.com")
+	}
+	for _, label := range []string{service[1:], proto[1:]} {
+		if err := checkLabel(label); err != nil {
+			return "", err
+		}
+	}
+
+	host, err := parseHostName(host)
+	if err != nil {
+		return "", err
+	}
+	return service + "." + proto + "." + host, nil
+}
+
+// isHostName reports whether target is a host name, rather than an IP address,
+// a CIDR prefix or a range of addresses.
+func isHostName(target string) bool {
+	_, err := netip.ParseAddr(target)
+	return err != nil && !isAddressRange(target)
+}
+
+// parseHostName validates an RFC 1123 host name, possibly internationalized,
+// and returns its ASCII form.
+func parseHostName(name string) (string, error) {
+	ascii := name
+	if !isASCII(name) || strings.Contains(strings.ToLower(name), "xn--") {
+		converted, err := idna.Lookup.ToASCII(name)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized host name: %w", err)
+		}
+		// Names already in punycode are only validated, to keep their case.
+		if !isASCII(name) {
+			ascii = converted
+		}
+	}
+
+	trimmed := strings.TrimSuffix(ascii, ".")
+	if len(trimmed) > maxHostNameLength {
+		return "", fmt.Errorf("host name is longer than %d characters", maxHostNameLength)
+	}
+
+	labels := strings.Split(trimmed, ".")
+	for _, label := range labels {
+		if err := checkLabel(label); err != nil {
+			return "", err
+		}
+	}
+	if isNumeric(labels[len(labels)-1]) {
+		return "", errors.New("invalid IP address")
+	}
+
+	return ascii, nil
+}
+
+// checkLabel validates a label of a host name, as defined by RFC 1123.
+func checkLabel(label string) error {
+	switch {
+	case label == "":
+		return errors.New("host name cannot contain empty labels")
+	case len(label) > maxLabelLength:
+		return fmt.Errorf("host name label %q is longer than %d characters", label, maxLabelLength)
+	case label[0] == '-' || label[len(label)-1] == '-':
+		return fmt.Errorf("host name label %q cannot start or end with a hyphen", label)
+	}
+
+	for _, r := range label {
+		if !isLetterDigitHyphen(r) {
+			return fmt.Errorf("host name label %q contains invalid character %q", label, r)
+		}
+	}
+	return nil
+}
+
+func isLetterDigitHyphen(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-'
+}
+
+func isNumeric(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package icmpreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr string
+	}{
+		{target: "8.8.8.8", want: "8.8.8.8"},
+		{target: "2001:db8::1", want: "2001:db8::1"},
+		{target: "fe80::1%eth0", want: "fe80::1%eth0"},
+		{target: "::ffff:192.0.2.1", want: "::ffff:192.0.2.1"},
+		{target: "192.0.2.0/28", want: "192.0.2.0/28"},
+		{target: "2001:db8::/126", want: "2001:db8::/126"},
+		{target: "www.Example.com", want: "www.Example.com"},
+		{target: "www.example.com.", want: "www.example.com."},
+		{target: "localhost", want: "localhost"},
+		{target: "1e100.net", want: "1e100.net"},
+		{target: "bücher.example", want: "xn--bcher-kva.example"},
+		{target: "münchen.de.", want: "xn--mnchen-3ya.de."},
+		{target: "", wantErr: "cannot be empty"},
+		{target: "www.example .com", wantErr: "cannot contain whitespace"},
+		{target: "https://www.example.com/", wantErr: "not a URL"},
+		{target: "www.example.com:443", wantErr: `cannot contain a port, ICMP has none: use "www.example.com" instead of port 443`},
+		{target: "[2001:db8::1]:80", wantErr: "cannot contain a port"},
+		{target: "192.0.2.0/33", wantErr: "invalid CIDR prefix"},
+		{target: "www.example.com/path", wantErr: "invalid CIDR prefix"},
+		{target: "256.1.1.1", wantErr: "invalid IP address"},
+		{target: "8.8.8", wantErr: "invalid IP address"},
+		{target: "www..example.com", wantErr: "empty labels"},
+		{target: "-www.example.com", wantErr: `label "-www" cannot start or end with a hyphen`},
+		{target: "www_1.example.com", wantErr: `label "www_1" contains invalid character '_'`},
+		{target: "a123456789012345678901234567890123456789012345678901234567890123.com", wantErr: "longer than 63 characters"},
+		{target: "xn--Bcher-kva.example", want: "xn--Bcher-kva.example"},
+		{target: "aא.example", wantErr: "invalid internationalized host name"},
+		{target: "xn--a.example", wantErr: "invalid internationalized host name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.target, func(t *testing.T) {
+				got, err := parseTarget(tt.target)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			},
+		)
+	}
+}
+
+func TestParseSRVName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{name: "_icmp._udp.example.com", want: "_icmp._udp.example.com"},
+		{name: "_ping._tcp.bücher.example.", want: "_ping._tcp.xn--bcher-kva.example."},
+		{name: "www.example.com", wantErr: "must be an SRV record name"},
+		{name: "_icmp.example.com", wantErr: "must be an SRV record name"},
+		{name: "_icmp._udp", wantErr: "must be an SRV record name"},
+		{name: "_icmp_._udp.example.com", wantErr: `label "icmp_" contains invalid character '_'`},
+		{name: "_icmp._udp.www..example.com", wantErr: "empty labels"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseSRVName(test.name)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package icmpreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// targetGroup is a group of targets sharing the same labels, as found in
+// Prometheus file_sd files.
+type targetGroup struct {
+	Targets []string          `json:"targets" yaml:"targets"`
+	Labels  map[string]string `json:"labels" yaml:"labels"`
+}
+
+// readTargetsFile reads the targets of a Prometheus file_sd file, in JSON or
+// YAML depending on its extension. Labels become attributes of the targets of
+// their group, except the tag label that sets their tag, and meta labels
+// starting with __ that are dropped. Files without any group are rejected.
+func readTargetsFile(path string) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []targetGroup
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&groups)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&groups)
+	default:
+		return nil, fmt.Errorf("unsupported extension %q: must be .json, .yaml or .yml", ext)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("%s has no target groups", path)
+	}
+
+	var targets []Target
+	for _, group := range groups {
+		tag, attributes := "", make(map[string]string, len(group.Labels))
+		for key, value := range group.Labels {
+			switch {
+			case strings.HasPrefix(key, "__"):
+			case key == AttrTag:
+				tag = value
+			default:
+				attributes[key] = value
+			}
+		}
+		for _, target := range group.Targets {
+			targets = append(targets, Target{Target: target, Tag: tag, Attributes: attributes})
+		}
+	}
+	return targets, nil
+}
+
+// targetsFile remembers the state of a targets file when it was last read, to
+// only read it again once it changed.
+type targetsFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// changed reports whether the file changed since the last call.
+func (f *targetsFile) changed() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+	f.modTime, f.size = info.ModTime(), info.Size()
+	return true, nil
+}
+
+// reloadTargets reads the targets file again when it changed, and pings the
+// targets of the config and of the file from then on. Files that cannot be
+// read, or contain invalid targets, are rejected and the previous targets are
+// kept. This includes empty files, which are often files being written.
+func (s *pingScraper) reloadTargets() error {
+	changed, err := s.targetsFile.changed()
+	if err != nil || !changed {
+		return err
+	}
+
+	fileTargets, err := readTargetsFile(s.targetsFile.path)
+	if err != nil {
+		return err
+	}
+
+	cfg := *s.cfg
+	cfg.Targets = append(slices.Clone(s.cfg.Targets), fileTargets...)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid targets in %s: %w", s.targetsFile.path, err)
+	}
+	targets, err := expandTargets(cfg.Targets, cfg.MaxTargetExpansion, cfg.AddressFamily)
+	if err != nil {
+		return fmt.Errorf("invalid targets in %s: %w", s.targetsFile.path, err)
+	}
+
+	if s.registry != nil {
+		if err := s.registry.replace(s.id, targetKeys(targets, s.addressFamily)); err != nil {
+			return err
+		}
+	}
+	s.targets = targets
+	s.forgetRemovedTargets()
+	_, scheduled := partitionTargets(targets)
+	s.scheduler.update(scheduled)
+	s.logger.Info("loaded targets file", zap.String("path", s.targetsFile.path), zap.Int("targets", len(targets)))
+	return nil
+}
+
+// forgetRemovedTargets drops the counts and states kept for targets that are
+// no longer pinged, so they do not pile up as the targets file changes.
+func (s *pingScraper) forgetRemovedTargets() {
+	keys := make(map[string]bool, len(s.targets))
+	for _, target := range s.targets {
+		keys[target.key(s.addressFamily)] = true
+	}
+	removed := func(target string, _ bool) bool { return !keys[target] }
+
+	for key, errs := range s.dnsErrors {
+		maps.DeleteFunc(errs.targets, removed)
+		if len(errs.targets) == 0 {
+			delete(s.dnsErrors, key)
+		}
+	}
+	for key, counts := range s.packetCounts {
+		maps.DeleteFunc(counts.targets, removed)
+		if len(counts.targets) == 0 {
+			delete(s.packetCounts, key)
+		}
+	}
+	s.states.forget(func(target string) bool { return !keys[target] })
+}
@@ -0,0 +1,118 @@
+package icmpreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTargetsFile(t *testing.T) {
+	expected := []Target{
+		{Target: "8.8.8.8", Tag: "dns", Attributes: map[string]string{"site": "paris"}},
+		{Target: "dns.google", Tag: "dns", Attributes: map[string]string{"site": "paris"}},
+		{Target: "192.0.2.0/30", Attributes: map[string]string{}},
+	}
+
+	for _, name := range []string{"targets.json", "targets.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			targets, err := readTargetsFile(filepath.Join("testdata", "targets", name))
+			require.NoError(t, err)
+			assert.Equal(t, expected, targets)
+		})
+	}
+}
+
+func TestReadTargetsFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		error string
+	}{
+		{
+			name:  "missing file",
+			path:  filepath.Join(dir, "missing.json"),
+			error: "no such file or directory",
+		},
+		{
+			name:  "unsupported extension",
+			path:  write("targets.txt", "8.8.8.8"),
+			error: `unsupported extension ".txt"`,
+		},
+		{
+			name:  "truncated json",
+			path:  write("truncated.json", `[{"targets": ["8.8.8.8"]`),
+			error: "cannot parse",
+		},
+		{
+			name:  "unknown json field",
+			path:  write("unknown.json", `[{"targets": ["8.8.8.8"], "ping_count": 5}]`),
+			error: `unknown field "ping_count"`,
+		},
+		{
+			name:  "unknown yaml field",
+			path:  filepath.Join("testdata", "targets", "invalid.yaml"),
+			error: "field ping_count not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readTargetsFile(test.path)
+			assert.ErrorContains(t, err, test.error)
+		})
+	}
+}
+
+func TestReadEmptyTargetsFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"empty.json":     "",
+		"empty.yaml":     "",
+		"no-groups.json": "[]",
+		"no-groups.yaml": "[]",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			targets, err := readTargetsFile(path)
+			assert.ErrorContains(t, err, "has no target groups")
+			assert.Empty(t, targets)
+		})
+	}
+}
+
+func TestTargetsFileChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o600))
+
+	file := &targetsFile{path: path}
+	changed, err := file.changed()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = file.changed()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	changed, err = file.changed()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	require.NoError(t, os.Remove(path))
+	_, err = file.changed()
+	assert.Error(t, err)
+}